@@ -1,61 +1,22 @@
-// Package main solves the Advent of Code 2015 Day 01 problem.
-package main
+// Package day01 solves the Advent of Code 2015 Day 01 problem.
+package day01
 
 import (
 	"fmt"
 	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strings"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
-
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
-
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-	logger.Infoln("Part 1:", part1)
-
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-	logger.Infoln("Part 2:", part2)
+func init() {
+	common.Register(2015, 1, common.SolutionFuncs[[]string]{
+		Parser: common.ParseLines,
+		P1:     Part1,
+		P2:     Part2,
+	})
 }
 
 // Part1 calculates the final floor Santa will arrive on.
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, input []string) (common.Answer, error) {
 	start := time.Now()
 	floor := 0
 
@@ -72,13 +33,13 @@ func Part1(input []string) (int, error) {
 		}
 	}
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return floor, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(floor), nil
 }
 
 // Part2 finds position of the first character that causes Santa to enter the
 // basement
-func Part2(input []string) (int, error) {
+func Part2(rc *common.RunContext, input []string) (common.Answer, error) {
 	start := time.Now()
 	floor := 0
 	position := 0
@@ -96,11 +57,11 @@ func Part2(input []string) (int, error) {
 
 			// Check if Santa has entered the basement
 			if floor == -1 {
-				logger.Infoln("Part 2 took:", time.Since(start))
-				return position, nil
+				rc.Logger.Infoln("Part 2 took:", time.Since(start))
+				return common.Int(position), nil
 			}
 		}
 	}
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return -1, fmt.Errorf("santa does not enter the basement")
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(-1), fmt.Errorf("santa does not enter the basement")
 }
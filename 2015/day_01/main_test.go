@@ -1,82 +1,13 @@
-// // Package main solves the Advent of Code 2015 Day 01 problem.
-package main
+// Package day01 solves the Advent of Code 2015 Day 01 problem.
+package day01
 
 import (
-	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strings"
+	"jonoricci/advent-of-code-go/common/golden"
 	"testing"
 )
 
-// MockInput returns a slice of strings which is the puzzle input.
-func MockInput() []string {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
-
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	return values
-}
-
-// TestPart1 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart1(t *testing.T) {
-	expectedValues := []int{138}
-	result, err := Part1(MockInput())
-	if err != nil {
-		t.Fatalf("Part1 returned an error: %v", err)
-	}
-
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		t.Errorf("Expected Part1 to return %d, got %d", expectedValues, result)
-	}
-}
-
-// TestPart2 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart2(t *testing.T) {
-	expectedValues := []int{1771}
-	result, err := Part2(MockInput())
-	if err != nil {
-		t.Fatalf("Part2 returned an error: %v", err)
-	}
-
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		t.Errorf("Expected Part2 to return %d, got %d", expectedValues, result)
-	}
+// FuzzParts ensures any instructions for Santa, however garbled, never make
+// parsing them or finding his floor panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2015, 1)
 }
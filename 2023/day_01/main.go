@@ -1,67 +1,27 @@
-// Package main solves the Advent of Code 2023 Day 01 problem.
-package main
+// Package day01 solves the Advent of Code 2023 Day 01 problem.
+package day01
 
 import (
 	"fmt"
 	"jonoricci/advent-of-code-go/common"
-	"log"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
-
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
-
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-
-	if err != nil {
-		logger.Fatalln(err)
-	}
-
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-
-	logger.Infoln("Part 1:", part1)
-	logger.Infoln("Part 2:", part2)
+func init() {
+	common.Register(2023, 1, common.SolutionFuncs[[]string]{
+		Parser: common.ParseLines,
+		P1:     Part1,
+		P2:     Part2,
+	})
 }
 
 // Part1 calculates the sum of two digit numbers from a slice of strings.
 // Each number is formed by the first and last digit of each string.
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, input []string) (common.Answer, error) {
 	start := time.Now()
 	sum := 0
 
@@ -92,14 +52,14 @@ func Part1(input []string) (int, error) {
 		sum += firstDigitInt*10 + lastDigitInt
 	}
 
-	logger.Infoln("Part 1 took:", time.Since(start)) // Log time taken to execute
-	return sum, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start)) // Log time taken to execute
+	return common.Int(sum), nil
 }
 
 // Part2 calculates the sum of two digit numbers from a slice of strings.
 // Each number is formed by the first and last digit, where digits can be
 // integers or spelled-out words in a provided map.
-func Part2(input []string) (int, error) {
+func Part2(rc *common.RunContext, input []string) (common.Answer, error) {
 	start := time.Now()
 
 	numberMap := map[string]int{
@@ -114,16 +74,16 @@ func Part2(input []string) (int, error) {
 		"nine":  9,
 	}
 	sum := 0 // Initalise sum to zero
-	for _, line := range input {
+	for i, line := range input {
 		firstDigit, lastDigit, err := searchLine(line, numberMap)
 		if err != nil {
-			logger.Errorln(err)
+			return common.Answer{}, fmt.Errorf("error in Part2 on line %d: %w", i+1, err)
 		}
 		sum += firstDigit*10 + lastDigit
 	}
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
 // searchLine extracts the first and last digits from a line.
@@ -148,7 +108,7 @@ func searchLine(line string, numberMap map[string]int) (int, int, error) {
 	if len(firstMatch) > 0 {
 		firstDigit, err = digitValue(firstMatch[0], numberMap)
 		if err != nil {
-			logger.Errorln(err)
+			return 0, 0, err
 		}
 	}
 
@@ -158,7 +118,7 @@ func searchLine(line string, numberMap map[string]int) (int, int, error) {
 		if len(lastMatch) > 0 {
 			lastDigit, err = digitValue(lastMatch[0], numberMap)
 			if err != nil {
-				logger.Errorln(err)
+				return 0, 0, err
 			}
 			break
 		}
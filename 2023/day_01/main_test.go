@@ -0,0 +1,33 @@
+// Package day01 solves the Advent of Code 2023 Day 01 problem.
+package day01
+
+import (
+	"jonoricci/advent-of-code-go/common/golden"
+	"testing"
+)
+
+// FuzzParts ensures any calibration document, however garbled, never makes
+// parsing it or either way of reading its digits panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 1)
+}
+
+// FuzzSearchLine ensures searchLine never panics on any line of input,
+// though it may return an error. Its other parameters are zero values.
+func FuzzSearchLine(f *testing.F) {
+	golden.AddLines(f, ".")
+	f.Fuzz(func(t *testing.T, text string) {
+		var numberMap map[string]int
+		searchLine(text, numberMap)
+	})
+}
+
+// FuzzDigitValue ensures digitValue never panics on any line of input,
+// though it may return an error. Its other parameters are zero values.
+func FuzzDigitValue(f *testing.F) {
+	golden.AddLines(f, ".")
+	f.Fuzz(func(t *testing.T, text string) {
+		var numberMap map[string]int
+		digitValue(text, numberMap)
+	})
+}
@@ -1,155 +1,146 @@
-// Package main solves the Advent of Code 2023 Day 02 problem.
-package main
+// Package day02 solves the Advent of Code 2023 Day 02 problem.
+package day02
 
 import (
 	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strconv"
-	"strings"
+	"jonoricci/advent-of-code-go/common/parse"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
-
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
-
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
+func init() {
+	common.Register(2023, 2, common.SolutionFuncs[[]Game]{
+		Parser: parseGames,
+		P1:     Part1,
+		P2:     Part2,
+	})
+}
 
-	if err != nil {
-		logger.Fatalln(err)
-	}
+// Game is a single game's ID and the subsets of cubes revealed in it.
+type Game struct {
+	ID      int
+	Subsets []Cubes
+}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
+// Cubes is how many cubes of each colour were revealed in one subset.
+type Cubes struct {
+	Red, Green, Blue int
+}
 
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
+// gamePattern matches a line of input such as "Game 1: 3 blue, 4 red; 1 red".
+var gamePattern = parse.MustCompile("Game {id:int}: {subsets}")
 
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalln(err)
+// parseGames splits each input line into a game ID and its subsets.
+func parseGames(rc *common.RunContext, input []string) ([]Game, error) {
+	var games []Game
+	for _, line := range parse.Lines(rc.Input, input) {
+		// Split up input line into gameID and subsets
+		m, err := gamePattern.Match(line)
+		if err != nil {
+			return nil, err
+		}
+		game := Game{ID: m.Int("id")}
+		for _, subset := range m.Span("subsets").Split("; ") {
+			cubes, err := countCubes(subset)
+			if err != nil {
+				return nil, err
+			}
+			game.Subsets = append(game.Subsets, cubes)
+		}
+		games = append(games, game)
 	}
-
-	logger.Infoln("Part 1:", part1)
-	logger.Infoln("Part 2:", part2)
+	return games, nil
 }
 
 // // Part1 takes an array of strings representing the game input and returns
 // the sum of the IDs of the games that are possible within the given cube
 // constraints.
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, games []Game) (common.Answer, error) {
 	start := time.Now()
 	sum := 0
 
-	for _, line := range input {
-		// Split up input line into gameID and subsets
-		parts := strings.Split(line, ": ")
-		gameID, _ := strconv.Atoi(strings.Split(parts[0], " ")[1])
-		subsets := strings.Split(parts[1], "; ")
-
-		if checkGamePossible(subsets) {
-			sum += gameID
+	for _, game := range games {
+		if checkGamePossible(game.Subsets) {
+			sum += game.ID
 		}
 	}
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
 // checkGamePossible takes an array of subsets of cubes and returns true if the
 // game is possible within the given cube constraints.
-func checkGamePossible(subsets []string) bool {
+func checkGamePossible(subsets []Cubes) bool {
 	const maxRed, maxGreen, maxBlue = 12, 13, 14 // Given by problem input
 
-	for _, subset := range subsets {
-		red, green, blue := countCubes(subset)
+	for _, cubes := range subsets {
 		// Check if any color exceeds its maximum allowed cubes
-		if red > maxRed || green > maxGreen || blue > maxBlue {
+		if cubes.Red > maxRed || cubes.Green > maxGreen || cubes.Blue > maxBlue {
 			return false // Game is not possible if any subset exceeds the constraints
 		}
 	}
 	return true // Return true if all subsets are within the constraints
 }
 
-// countCubes takes a string representing a subset of cubes and returns the
+// countCubes takes a subset of cubes such as "3 blue, 4 red" and returns the
 // count of red, green, and blue cubes in that subset.
-func countCubes(subset string) (int, int, int) {
-	red, green, blue := 0, 0, 0 // Initalise at zero
-	cubes := strings.Split(subset, ", ")
+func countCubes(subset parse.Span) (Cubes, error) {
+	var cubes Cubes // Initalise at zero
 
-	for _, cube := range cubes {
-		parts := strings.Split(cube, " ")
-		count, _ := strconv.Atoi(parts[0]) // Parse the count of cubes
-		colour := parts[1]                 // Get the colour of cubes
+	for _, cube := range subset.Split(", ") {
+		countStr, colour, found := cube.TrimSpace().Cut(" ")
+		if !found {
+			return Cubes{}, cube.Errorf(0, "expected a count and a colour, got %q", cube.Text)
+		}
+		count, err := countStr.Int() // Parse the count of cubes
+		if err != nil {
+			return Cubes{}, err
+		}
 
 		// Increment count of cube colour
-		switch colour {
+		switch colour.Text {
 		case "red":
-			red += count
+			cubes.Red += count
 		case "green":
-			green += count
+			cubes.Green += count
 		case "blue":
-			blue += count
+			cubes.Blue += count
+		default:
+			return Cubes{}, colour.Errorf(0, "unknown colour %q", colour.Text)
 		}
 	}
-	return red, green, blue
+	return cubes, nil
 }
 
 // Part2 calculates the sum of the powers of the minimum sets of cubes needed
 // for each game.
-func Part2(input []string) (int, error) {
+func Part2(rc *common.RunContext, games []Game) (common.Answer, error) {
 	start := time.Now()
 	sum := 0
 
-	for _, line := range input {
-		// Split up input line into gameID and subsets
-		parts := strings.Split(line, ": ")
-		subsets := strings.Split(parts[1], "; ")
+	for _, game := range games {
 		// Find the minimum cubes required
-		minRed, minGreen, minBlue := findMinimumSet(subsets)
+		minRed, minGreen, minBlue := findMinimumSet(game.Subsets)
 		sum += minRed * minGreen * minBlue // Power of the set
 	}
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
 // findMinimumSet returns the minimum number of coloured cubes needed for a game
-func findMinimumSet(subsets []string) (int, int, int) {
+func findMinimumSet(subsets []Cubes) (int, int, int) {
 	minRed, minGreen, minBlue := 0, 0, 0
 
-	for _, subset := range subsets {
-		red, green, blue := countCubes(subset)
-		if red > minRed {
-			minRed = red
+	for _, cubes := range subsets {
+		if cubes.Red > minRed {
+			minRed = cubes.Red
 		}
-		if green > minGreen {
-			minGreen = green
+		if cubes.Green > minGreen {
+			minGreen = cubes.Green
 		}
-		if blue > minBlue {
-			minBlue = blue
+		if cubes.Blue > minBlue {
+			minBlue = cubes.Blue
 		}
 	}
 	return minRed, minGreen, minBlue
@@ -0,0 +1,23 @@
+// Package day02 solves the Advent of Code 2023 Day 02 problem.
+package day02
+
+import (
+	"jonoricci/advent-of-code-go/common/golden"
+	"jonoricci/advent-of-code-go/common/parse"
+	"testing"
+)
+
+// FuzzParts ensures any record of games, however garbled, never makes parsing
+// it or checking the cubes panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 2)
+}
+
+// FuzzCountCubes ensures countCubes never panics on any line of input,
+// though it may return an error.
+func FuzzCountCubes(f *testing.F) {
+	golden.AddLines(f, ".")
+	f.Fuzz(func(t *testing.T, text string) {
+		countCubes(parse.Span{File: "fuzz", Line: 1, Col: 1, Text: text})
+	})
+}
@@ -0,0 +1,13 @@
+// Package day03 solves the Advent of Code 2023 Day 03 problem.
+package day03
+
+import (
+	"jonoricci/advent-of-code-go/common/golden"
+	"testing"
+)
+
+// FuzzParts ensures any engine schematic, however garbled, never makes parsing
+// it or finding its part numbers and gears panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 3)
+}
@@ -1,102 +1,86 @@
-// Package main solves the Advent of Code 2023 Day 04 problem.
-package main
+// Package day04 solves the Advent of Code 2023 Day 04 problem.
+package day04
 
 import (
 	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strconv"
-	"strings"
+	"jonoricci/advent-of-code-go/common/parse"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
+func init() {
+	common.Register(2023, 4, common.SolutionFuncs[[]Card]{
+		Parser: parseCards,
+		P1:     Part1,
+		P2:     Part2,
+	})
+}
 
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
+// Card is a scratchcard's winning numbers and the numbers you have.
+type Card struct {
+	WinningNums []int
+	YourNums    []int
+}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
+// cardPattern matches a line of input such as "Card 1: 41 48 | 83 86 6".
+var cardPattern = parse.MustCompile("Card {id:int}: {winning} | {yours}")
 
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
+// parseCards splits each line into a card's winning numbers and your numbers.
+func parseCards(rc *common.RunContext, input []string) ([]Card, error) {
+	var cards []Card
+	for _, line := range parse.Lines(rc.Input, input) {
+		// Split up each line to get two lists of numbers
+		m, err := cardPattern.Match(line)
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		logger.Fatalln(err)
-	}
+		winningNums, err := m.Span("winning").FieldInts()
+		if err != nil {
+			return nil, err
+		}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
+		yourNums, err := m.Span("yours").FieldInts()
+		if err != nil {
+			return nil, err
+		}
 
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalln(err)
+		cards = append(cards, Card{WinningNums: winningNums, YourNums: yourNums})
 	}
+	return cards, nil
+}
 
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalln(err)
+// matchCount counts how many of your numbers are winning numbers.
+func (c Card) matchCount() int {
+	// Create a map for winning numbers lookup
+	winningNumsMap := make(map[int]bool)
+	for _, num := range c.WinningNums {
+		winningNumsMap[num] = true
 	}
 
-	logger.Infoln("Part 1:", part1)
-	logger.Infoln("Part 2:", part2)
+	count := 0
+	for _, num := range c.YourNums {
+		if winningNumsMap[num] {
+			count++
+		}
+	}
+	return count
 }
 
 // Part1 processes a list of scratchcards, calculates the score for each card
 // based on the number of matching numbers with the winning numbers, and returns
 // the total score of all cards.
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, cards []Card) (common.Answer, error) {
 	start := time.Now()
 	sum := 0
 
-	for _, line := range input {
-		// Split up each line to get two slices
-		colonIndex := strings.Index(line, ":")
-		line = line[colonIndex+2:]
-		parts := strings.Split(line, "|")
-
-		winningNumsStr := strings.Fields(strings.TrimSpace(parts[0]))
-		yourNumsStr := strings.Fields(strings.TrimSpace(parts[1]))
-
-		winningNums, err := convertToIntSlice(winningNumsStr)
-		if err != nil {
-			logger.Fatalln(err)
-		}
-
-		yourNums, err := convertToIntSlice(yourNumsStr)
-		if err != nil {
-			logger.Fatalln(err)
-		}
-
-		// Create a map for winning numbers lookup
-		winningNumsMap := make(map[int]bool)
-		for _, num := range winningNums {
-			winningNumsMap[num] = true
-		}
-
-		// Check for matches and calculate score
+	for _, card := range cards {
+		// First match is worth one point, each match after doubles it
 		score := 0
-		for _, num := range yourNums {
-			if winningNumsMap[num] {
-				if score == 0 {
-					score = 1
-				} else {
-					score *= 2
-				}
+		for i := 0; i < card.matchCount(); i++ {
+			if score == 0 {
+				score = 1
+			} else {
+				score *= 2
 			}
 		}
 
@@ -104,69 +88,27 @@ func Part1(input []string) (int, error) {
 		sum += score
 	}
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return sum, nil
-}
-
-// convertToIntSlice converts a slice of strings to a slice of ints
-func convertToIntSlice(strSlice []string) ([]int, error) {
-	var intSlice []int
-	for _, str := range strSlice {
-		num, err := strconv.Atoi(str)
-		if err != nil {
-			logger.Fatalln(err)
-		}
-		intSlice = append(intSlice, num)
-	}
-	return intSlice, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
 // Part2 processes the scratchcards according to the new rules where each
 // matching number wins additional scratchcards. It returns the total number of
 // scratchcards, including both the original and the won copies.
-func Part2(input []string) (int, error) {
+func Part2(rc *common.RunContext, cards []Card) (common.Answer, error) {
 	start := time.Now()
 
 	// Store the number of copies for each card
-	cardCopies := make([]int, len(input))
+	cardCopies := make([]int, len(cards))
 	for i := range cardCopies {
 		cardCopies[i] = 1 // Each card starts with 1 copy (itself)
 	}
 
 	totalCards := 0
 
-	for i := 0; i < len(input); i++ {
-		// Split up each line to get two slices
-		line := input[i]
-		colonIndex := strings.Index(line, ":")
-		line = line[colonIndex+2:]
-		parts := strings.Split(line, "|")
-
-		winningNumsStr := strings.Fields(strings.TrimSpace(parts[0]))
-		yourNumsStr := strings.Fields(strings.TrimSpace(parts[1]))
-
-		winningNums, err := convertToIntSlice(winningNumsStr)
-		if err != nil {
-			logger.Fatalln(err)
-		}
-
-		yourNums, err := convertToIntSlice(yourNumsStr)
-		if err != nil {
-			logger.Fatalln(err)
-		}
-
-		winningNumsMap := make(map[int]bool)
-		for _, num := range winningNums {
-			winningNumsMap[num] = true
-		}
-
+	for i, card := range cards {
 		// Count matches instead of calculating score
-		matchCount := 0
-		for _, num := range yourNums {
-			if winningNumsMap[num] {
-				matchCount++
-			}
-		}
+		matchCount := card.matchCount()
 
 		// For each match, add a copy to subsequent cards
 		for j := 1; j <= matchCount; j++ {
@@ -181,6 +123,6 @@ func Part2(input []string) (int, error) {
 		totalCards += copies
 	}
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return totalCards, nil
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(totalCards), nil
 }
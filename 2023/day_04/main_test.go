@@ -0,0 +1,13 @@
+// Package day04 solves the Advent of Code 2023 Day 04 problem.
+package day04
+
+import (
+	"jonoricci/advent-of-code-go/common/golden"
+	"testing"
+)
+
+// FuzzParts ensures any pile of scratchcards, however garbled, never makes
+// parsing them or scoring them panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 4)
+}
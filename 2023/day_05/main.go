@@ -1,114 +1,76 @@
-// Package main solves the Advent of Code 2023 Day 05 problem.
-package main
+// Package day05 solves the Advent of Code 2023 Day 05 problem.
+package day05
 
 import (
 	"fmt"
 	"jonoricci/advent-of-code-go/common"
-	"log"
+	"jonoricci/advent-of-code-go/common/interval"
+	"jonoricci/advent-of-code-go/common/parse"
 	"math"
+	"math/rand"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
-
-// Using global var for input header names
-var sectionHeaders = []string{
-	"seed-to-soil map:",
-	"soil-to-fertilizer map:",
-	"fertilizer-to-water map:",
-	"water-to-light map:",
-	"light-to-temperature map:",
-	"temperature-to-humidity map:",
-	"humidity-to-location map:",
-}
-
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
-
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-
-	if err != nil {
-		logger.Fatalf("Couldn't read input file: %v", err)
-	}
-
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalf("Part 1: %v", err)
-	}
-
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalf("Part 2: %v", err)
-	}
-
-	logger.Infoln("Part 1:", part1)
-	logger.Infoln("Part 2:", part2)
+func init() {
+	common.Register(2023, 5, common.SolutionFuncs[Almanac]{
+		Parser: parseInputData,
+		P1:     Part1,
+		P2:     Part2,
+	})
+	common.RegisterGenerator(2023, 5, generateAlmanac)
 }
 
 // Part1 treats each seed as an individual integer and finds the lowest location
 // number corresponding to these seeds.
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, almanac Almanac) (common.Answer, error) {
 	start := time.Now()
 
-	// Pass `false` to parseInputData to use seeds as individual ints
-	seeds, parsedMaps, err := parseInputData(input, false)
+	lowestLocation, err := processSeeds(rc, almanac.Seeds, almanac.Maps)
 	if err != nil {
-		return 0, fmt.Errorf("error in Part1: %w", err)
+		return common.Answer{}, err
 	}
 
-	lowestLocation := processSeeds(seeds, parsedMaps)
-
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return lowestLocation, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(lowestLocation), nil
 }
 
-// processSeeds processes a list of seeds through a series of maps defined in
-// parsedMaps. It calculates and returns the lowest location number obtained
-// from these mappings.
-func processSeeds(seeds []int, parsedMaps map[string][]RangeMap) int {
-	lowestLocation := math.MaxInt32
+// processSeeds processes a list of seeds through each of the almanac's maps
+// in turn. It calculates and returns the lowest location number obtained
+// from these mappings, or an error if there are no seeds. It stops with the
+// context's error if the run is cancelled, as the tests also use it to check
+// Part 2 one seed at a time, which can be a lot of seeds.
+func processSeeds(rc *common.RunContext, seeds []int, maps []Mapping) (int, error) {
+	if len(seeds) == 0 {
+		return 0, fmt.Errorf("no seeds to plant")
+	}
+	lowestLocation := math.MaxInt
 
-	for _, seed := range seeds {
-		logger.Debug("Processing seed:", seed)
+	for i, seed := range seeds {
+		// Checking every seed would slow things down, so check now and then
+		if i%(1<<20) == 0 {
+			if err := rc.Err(); err != nil {
+				return 0, err
+			}
+		}
+		rc.Logger.Debug("Processing seed:", seed)
 		location := seed
 
-		for _, header := range sectionHeaders {
-			logger.Debug("Processing header:", header)
-			location = applyMapping(location, parsedMaps[header])
+		for _, m := range maps {
+			rc.Logger.Debugln("Processing map:", m.From, "to", m.To)
+			location = applyMapping(location, m.Ranges)
 		}
 
 		if location < lowestLocation {
 			lowestLocation = location
-			logger.Debug("Lowest location so far is:", lowestLocation)
+			rc.Logger.Debug("Lowest location so far is:", lowestLocation)
 		}
 	}
 
-	logger.Debug("Lowest location is:", lowestLocation)
-	return lowestLocation
+	rc.Logger.Debug("Lowest location is:", lowestLocation)
+	return lowestLocation, nil
 }
 
 // applyMapping applies a single RangeMap to a seed number and returns the
@@ -123,49 +85,70 @@ func applyMapping(number int, mappings []RangeMap) int {
 	return number // No mapping found, return original number
 }
 
+// Almanac holds the seed numbers from the first line of input and the maps
+// which convert them, step by step, into locations.
+type Almanac struct {
+	Seeds []int
+	Maps  []Mapping
+}
+
+// Mapping is one "X-to-Y map:" section of the almanac, converting numbers of
+// one category, such as seed, into another, such as soil.
+type Mapping struct {
+	From   string
+	To     string
+	Ranges []RangeMap
+}
+
+// headerPattern matches the first line of a map section, such as
+// "seed-to-soil map:".
+var headerPattern = parse.MustCompile("{from}-to-{to} map:")
+
 // parseInputData parses the input data into seeds and a series of mappings.
-// It takes a flag seedRange to determine whether to treat seeds as individual
-// numbers or as ranges. It returns a slice of seed numbers and a map of
-// RangeMaps for each mapping step.
-func parseInputData(input []string, seedRange bool) ([]int, map[string][]RangeMap, error) {
-	// Get the seeds
-	seeds, err := extractSeeds(input[0], seedRange)
-	if err != nil {
-		logger.Fatalln("Parsing seeds:", err)
+// Seeds are kept as the individual numbers listed, Part2 pairs them up into
+// ranges. The sections are separated by blank lines and each map must carry
+// on from the category the last one ended with, starting from seed and
+// ending at location.
+func parseInputData(rc *common.RunContext, input []string) (Almanac, error) {
+	sections := parse.Sections(parse.Lines(rc.Input, input))
+	if len(sections) == 0 {
+		return Almanac{}, fmt.Errorf("empty input")
 	}
 
-	// Map for storing the parsed maps
-	parsedMaps := make(map[string][]RangeMap)
-
-	var currentHeader string
-	var currentSection []string
+	// Get the seeds, which are a section of their own
+	if len(sections[0]) > 1 {
+		return Almanac{}, sections[0][1].Errorf(0, "expected a blank line after the seeds")
+	}
+	seeds, err := extractSeeds(sections[0][0])
+	if err != nil {
+		return Almanac{}, err
+	}
+	rc.Logger.Debug("Found seeds:", seeds)
 
-	for _, line := range input[1:] { // Skip first line containing seeds
-		if isHeader(line, sectionHeaders) {
-			if currentHeader != "" {
-				// Parse previous section
-				parsedMaps[currentHeader], err = parseMap(currentSection)
-				if err != nil {
-					logger.Fatalln("Parsing %S:", currentHeader, err)
-				}
-			}
-			// Reset for new section
-			currentHeader = line
-			currentSection = []string{}
-		} else {
-			currentSection = append(currentSection, line)
+	// Every other section is a map, with a header and then its ranges
+	var maps []Mapping
+	category := "seed"
+	for _, section := range sections[1:] {
+		header, err := headerPattern.Match(section[0])
+		if err != nil {
+			return Almanac{}, err
+		}
+		if header.Text("from") != category {
+			return Almanac{}, section[0].Errorf(0, "expected a map from %s, got %s", category, header.Text("from"))
 		}
-	}
 
-	// Parse last section
-	if currentHeader != "" {
-		parsedMaps[currentHeader], err = parseMap(currentSection)
+		ranges, err := parseMap(section[1:])
 		if err != nil {
-			return nil, nil, fmt.Errorf("parsing %s, %v", currentHeader, err)
+			return Almanac{}, err
 		}
+		maps = append(maps, Mapping{From: category, To: header.Text("to"), Ranges: ranges})
+		category = header.Text("to")
+	}
+	if category != "location" {
+		return Almanac{}, &parse.Error{File: rc.Input, Err: fmt.Errorf("maps end at %s, expected location", category)}
 	}
 
-	return seeds, parsedMaps, nil
+	return Almanac{Seeds: seeds, Maps: maps}, nil
 }
 
 // RangeMap defines a mapping from a source range to a destination range in
@@ -177,101 +160,148 @@ type RangeMap struct {
 	Length      int
 }
 
-// extractSeeds processes the first line of input to extract seed numbers.
-// It handles two formats: individual seeds or ranges of seeds, based on the
-// isRangeFormat flag. It returns a slice of all seed numbers to be processed.
-func extractSeeds(input string, isRangeFormat bool) ([]int, error) {
-	parts := strings.SplitN(input, ":", 2)
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid seed input format: %s", input)
+// extractSeeds processes the first line of input, "seeds: 79 14 55 13", to
+// extract the seed numbers.
+func extractSeeds(line parse.Span) ([]int, error) {
+	key, value, err := line.KeyValue(":")
+	if err != nil {
+		return nil, err
 	}
+	if key != "seeds" {
+		return nil, line.Errorf(0, "expected seeds, got %q", key)
+	}
+	return value.FieldInts()
+}
 
-	numberParts := strings.Fields(parts[1])
-	var seeds []int
+// seedRanges treats the seed numbers as pairs of a start and a length (Part
+// Two format) and returns the set of seeds in those ranges.
+func seedRanges(numbers []int) (interval.Set, error) {
+	if len(numbers)%2 != 0 {
+		return nil, fmt.Errorf("seed ranges need an even count of numbers, got %d", len(numbers))
+	}
 
-	if isRangeFormat {
-		// Process as ranges (Part Two format)
-		for i := 0; i < len(numberParts); i += 2 {
-			start, err := strconv.Atoi(numberParts[i])
-			if err != nil {
-				return nil, err
-			}
-			logger.Debug("Found seed:", start)
-			length, err := strconv.Atoi(numberParts[i+1])
-			if err != nil {
-				return nil, err
-			}
-			logger.Debug("Seed has length of:", length)
-			for j := 0; j < length; j++ {
-				seeds = append(seeds, start+j)
-			}
-		}
-	} else {
-		// Process each number as an individual seed (Part One format)
-		for _, part := range numberParts {
-			seed, err := strconv.Atoi(part)
-			if err != nil {
-				return nil, err
-			}
-			logger.Debug("Found seed:", seed)
-			seeds = append(seeds, seed)
-		}
+	var ranges []interval.Interval
+	for i := 0; i < len(numbers); i += 2 {
+		ranges = append(ranges, interval.FromLength(numbers[i], numbers[i+1]))
 	}
-	return seeds, nil
+	return interval.Merge(ranges...), nil
+}
+
+// Source returns the range of numbers the RangeMap converts.
+func (m RangeMap) Source() interval.Interval {
+	return interval.FromLength(m.SourceStart, m.Length)
 }
 
-// isHeader checks if a given line in the input matches any of the predefined
-// section headers. It is used to identify the start of a new mapping section
-// in the input.
-func isHeader(line string, sectionHeaders []string) bool {
-	for _, header := range sectionHeaders {
-		if line == header {
-			return true
+// mapRanges converts every number in a set through one map at once. Each range
+// is cut wherever a RangeMap starts or ends, so every piece is either inside a
+// single RangeMap and moved as a whole, or outside them all and kept as it is.
+func mapRanges(set interval.Set, mappings []RangeMap) interval.Set {
+	sources := make([]interval.Interval, len(mappings))
+	for i, m := range mappings {
+		sources[i] = m.Source()
+	}
+
+	var mapped []interval.Interval
+	for _, r := range set {
+		for _, piece := range r.SplitBy(sources) {
+			// Every number in the piece moves by the same amount as its first
+			offset := applyMapping(piece.Start, mappings) - piece.Start
+			mapped = append(mapped, piece.Shift(offset))
 		}
 	}
-	return false
+	return interval.Merge(mapped...)
 }
 
-// parseMap parses a slice of strings representing lines of a map section.
+// parseMap parses the lines of a map section, "destination source length".
 // It converts each line into a RangeMap struct, which defines the mappings
 // from one category to another. It returns a slice of RangeMaps for the
 // section.
-func parseMap(lines []string) ([]RangeMap, error) {
+func parseMap(lines []parse.Span) ([]RangeMap, error) {
 	var maps []RangeMap
 	for _, line := range lines {
-		parts := strings.Fields(line)
-		if len(parts) != 3 {
-			return nil, fmt.Errorf("invalid map line: %s", line)
+		numbers, err := line.FieldInts()
+		if err != nil {
+			return nil, err
 		}
-		sourceStart, err1 := strconv.Atoi(parts[1])
-		destStart, err2 := strconv.Atoi(parts[0])
-		length, err3 := strconv.Atoi(parts[2])
-		if err1 != nil || err2 != nil || err3 != nil {
-			return nil, fmt.Errorf("error parsing map line: %s", line)
+		if len(numbers) != 3 {
+			return nil, line.Errorf(0, "expected 3 numbers in map line, got %d", len(numbers))
 		}
 		maps = append(maps, RangeMap{
-			SourceStart: sourceStart,
-			DestStart:   destStart,
-			Length:      length,
+			SourceStart: numbers[1],
+			DestStart:   numbers[0],
+			Length:      numbers[2],
 		})
 	}
 	return maps, nil
 }
 
-// Part2 treats the seeds as ranges, expands these ranges into individual seeds,
-// and finds the lowest location number. This took me at least 5 minutes to
-// execute so performance is not the best.
-func Part2(input []string) (int, error) {
+// Part2 treats the seeds as ranges and finds the lowest location number. The
+// ranges hold billions of seeds, so rather than following each seed they are
+// mapped a whole range at a time.
+func Part2(rc *common.RunContext, almanac Almanac) (common.Answer, error) {
 	start := time.Now()
 
-	// Pass `true` to parseInputData to use seeds as a range
-	seeds, parsedMaps, err := parseInputData(input, true)
+	// Pair up the seed numbers as ranges
+	seeds, err := seedRanges(almanac.Seeds)
 	if err != nil {
-		return 0, fmt.Errorf("error in Part2: %w", err)
+		return common.Answer{}, fmt.Errorf("error in Part2: %w", err)
 	}
 
-	lowestLocation := processSeeds(seeds, parsedMaps)
+	for _, m := range almanac.Maps {
+		seeds = mapRanges(seeds, m.Ranges)
+		rc.Logger.Debugln("Mapped to", m.To, "ranges:", seeds)
+	}
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return lowestLocation, nil
+	lowestLocation, found := seeds.Min()
+	if !found {
+		return common.Answer{}, fmt.Errorf("no seeds to plant")
+	}
+
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(lowestLocation), nil
+}
+
+// categories are the steps an almanac maps a seed through, in order.
+var categories = []string{"seed", "soil", "fertilizer", "water", "light", "temperature", "humidity", "location"}
+
+// generateAlmanac makes a random almanac. Size is how many seed ranges there
+// are and how many ranges each map has, and every number is below 100 times
+// the size, so small almanacs can still be checked a seed at a time. Like the
+// real almanacs each map cuts the numbers into ranges and shuffles them
+// about, though some ranges are left out so they keep their own numbers.
+func generateAlmanac(r *rand.Rand, size int) []string {
+	limit := 100 * size
+
+	seeds := []string{"seeds:"}
+	for i := 0; i < size; i++ {
+		start := r.Intn(limit)
+		length := 1 + r.Intn(limit-start)
+		seeds = append(seeds, strconv.Itoa(start), strconv.Itoa(length))
+	}
+	lines := []string{strings.Join(seeds, " ")}
+
+	for i := 1; i < len(categories); i++ {
+		lines = append(lines, "", fmt.Sprintf("%s-to-%s map:", categories[i-1], categories[i]))
+
+		// Cut the numbers into pieces at random points, dropping any cut
+		// made twice
+		cuts := []int{0, limit}
+		for j := 1; j < size; j++ {
+			cuts = append(cuts, 1+r.Intn(limit-1))
+		}
+		slices.Sort(cuts)
+		cuts = slices.Compact(cuts)
+
+		// Lay the pieces out again in a shuffled order
+		order := r.Perm(len(cuts) - 1)
+		dest := 0
+		for _, j := range order {
+			length := cuts[j+1] - cuts[j]
+			if r.Intn(5) > 0 {
+				lines = append(lines, fmt.Sprintf("%d %d %d", dest, cuts[j], length))
+			}
+			dest += length
+		}
+	}
+	return lines
 }
@@ -0,0 +1,122 @@
+// Package day05 solves the Advent of Code 2023 Day 05 problem.
+package day05
+
+import (
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/check"
+	"jonoricci/advent-of-code-go/common/golden"
+	"jonoricci/advent-of-code-go/common/parse"
+	"testing"
+)
+
+// FuzzParts ensures any almanac, however garbled, never makes parsing it or
+// mapping its seeds to locations panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 5)
+}
+
+// FuzzExtractSeeds ensures extractSeeds never panics on any line of input,
+// though it may return an error.
+func FuzzExtractSeeds(f *testing.F) {
+	golden.AddLines(f, ".")
+	f.Fuzz(func(t *testing.T, text string) {
+		extractSeeds(parse.Span{File: "fuzz", Line: 1, Col: 1, Text: text})
+	})
+}
+
+// FuzzParseMap ensures parseMap never panics on any section of input,
+// though it may return an error.
+func FuzzParseMap(f *testing.F) {
+	golden.AddSections(f, ".")
+	f.Fuzz(func(t *testing.T, text string) {
+		parseMap(parse.Lines("fuzz", common.SplitLines(text)))
+	})
+}
+
+// TestNoSeeds ensures both parts report an almanac without seeds rather than
+// giving a made up location.
+func TestNoSeeds(t *testing.T) {
+	rc := common.Background()
+	if _, err := Part1(rc, Almanac{}); err == nil {
+		t.Error("Expected Part1 to return an error")
+	}
+	if _, err := Part2(rc, Almanac{}); err == nil {
+		t.Error("Expected Part2 to return an error")
+	}
+}
+
+// TestMapRangesProperty ensures mapping whole ranges at once finds the same
+// lowest location as following every seed through the maps one at a time.
+func TestMapRangesProperty(t *testing.T) {
+	rc := common.Background()
+	perSeed := func(almanac Almanac) (common.Answer, error) {
+		var seeds []int
+		for i := 0; i < len(almanac.Seeds); i += 2 {
+			for seed := almanac.Seeds[i]; seed < almanac.Seeds[i]+almanac.Seeds[i+1]; seed++ {
+				seeds = append(seeds, seed)
+			}
+		}
+		lowest, err := processSeeds(rc, seeds, almanac.Maps)
+		return common.Int(lowest), err
+	}
+	byRange := func(almanac Almanac) (common.Answer, error) {
+		return Part2(rc, almanac)
+	}
+	check.Equal(t, check.Config{MaxSize: 10}, check.Puzzle[Almanac](t, 2023, 5), shrinkAlmanac, perSeed, byRange)
+}
+
+// shrinkAlmanac drops and shrinks seed ranges, maps and their RangeMaps,
+// always keeping at least one seed.
+func shrinkAlmanac(almanac Almanac) []Almanac {
+	var smaller []Almanac
+
+	var pairs [][2]int
+	for i := 0; i < len(almanac.Seeds); i += 2 {
+		pairs = append(pairs, [2]int{almanac.Seeds[i], almanac.Seeds[i+1]})
+	}
+	shrinkPair := func(p [2]int) [][2]int {
+		var pairs [][2]int
+		for _, start := range check.Int(p[0]) {
+			pairs = append(pairs, [2]int{start, p[1]})
+		}
+		for _, length := range check.IntAbove(1)(p[1]) {
+			pairs = append(pairs, [2]int{p[0], length})
+		}
+		return pairs
+	}
+	for _, seedPairs := range check.Slice(pairs, 1, shrinkPair) {
+		var seeds []int
+		for _, p := range seedPairs {
+			seeds = append(seeds, p[0], p[1])
+		}
+		smaller = append(smaller, Almanac{Seeds: seeds, Maps: almanac.Maps})
+	}
+
+	for _, maps := range check.Slice(almanac.Maps, 0, shrinkMapping) {
+		smaller = append(smaller, Almanac{Seeds: almanac.Seeds, Maps: maps})
+	}
+	return smaller
+}
+
+// shrinkMapping drops and shrinks a map's RangeMaps.
+func shrinkMapping(m Mapping) []Mapping {
+	shrinkRange := func(rm RangeMap) []RangeMap {
+		var ranges []RangeMap
+		for _, n := range check.Int(rm.SourceStart) {
+			ranges = append(ranges, RangeMap{SourceStart: n, DestStart: rm.DestStart, Length: rm.Length})
+		}
+		for _, n := range check.Int(rm.DestStart) {
+			ranges = append(ranges, RangeMap{SourceStart: rm.SourceStart, DestStart: n, Length: rm.Length})
+		}
+		for _, n := range check.IntAbove(1)(rm.Length) {
+			ranges = append(ranges, RangeMap{SourceStart: rm.SourceStart, DestStart: rm.DestStart, Length: n})
+		}
+		return ranges
+	}
+
+	var smaller []Mapping
+	for _, ranges := range check.Slice(m.Ranges, 0, shrinkRange) {
+		smaller = append(smaller, Mapping{From: m.From, To: m.To, Ranges: ranges})
+	}
+	return smaller
+}
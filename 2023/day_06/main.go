@@ -1,156 +1,188 @@
-// Package main solves the Advent of Code 2023 Day 06 problem.
-package main
+// Package day06 solves the Advent of Code 2023 Day 06 problem.
+package day06
 
 import (
 	"fmt"
 	"jonoricci/advent-of-code-go/common"
-	"log"
+	"jonoricci/advent-of-code-go/common/mathx"
+	"jonoricci/advent-of-code-go/common/parse"
+	"math/rand"
 	"strconv"
 	"strings"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
+func init() {
+	common.Register(2023, 6, common.SolutionFuncs[Races]{
+		Parser: parseRaces,
+		P1:     Part1,
+		P2:     Part2,
+	})
+	common.RegisterGenerator(2023, 6, generateRaces)
+}
 
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
+// Race is how long a race lasts and the record distance to beat.
+type Race struct {
+	Time   int
+	Record int
+}
+
+// Races is the input read both ways: as separate races for Part 1 and, with
+// the spaces between the numbers ignored, as one long race for Part 2.
+type Races struct {
+	Races []Race
+	Long  Race
+}
+
+// parseRaces reads the "Time:" and "Distance:" lines into races.
+func parseRaces(rc *common.RunContext, input []string) (Races, error) {
+	lines := parse.Lines(rc.Input, input)
+	if len(lines) != 2 {
+		return Races{}, &parse.Error{File: rc.Input, Err: fmt.Errorf("expected a Time and a Distance line, got %d lines", len(lines))}
 	}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
+	times, longTime, err := parseNumbers(lines[0], "Time")
 	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
+		return Races{}, err
 	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-
+	records, longRecord, err := parseNumbers(lines[1], "Distance")
 	if err != nil {
-		logger.Fatalln(err)
+		return Races{}, err
+	}
+	if len(times) != len(records) {
+		return Races{}, lines[1].Errorf(0, "expected %d distances to match the times, got %d", len(times), len(records))
 	}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
+	races := Races{Long: Race{Time: longTime, Record: longRecord}}
+	for i := range times {
+		races.Races = append(races.Races, Race{Time: times[i], Record: records[i]})
+	}
+	rc.Logger.Debugln("Races:", races.Races, "Long race:", races.Long)
+	return races, nil
+}
 
-	// Execute Part 1
-	part1, err := Part1(values)
+// parseNumbers reads a line such as "Time:  7  15   30", returning each
+// number and the number made by joining them all together, here 71530.
+func parseNumbers(line parse.Span, key string) ([]int, int, error) {
+	got, value, err := line.KeyValue(":")
 	if err != nil {
-		logger.Fatalln(err)
+		return nil, 0, err
+	}
+	if got != key {
+		return nil, 0, line.Errorf(0, "expected %q, got %q", key, got)
 	}
 
-	// Execute Part 2
-	part2, err := Part2(values)
+	numbers, err := value.FieldInts()
 	if err != nil {
-		logger.Fatalln(err)
+		return nil, 0, err
 	}
 
-	logger.Infoln("Part 1:", part1)
-	logger.Infoln("Part 2:", part2)
-}
-
-// Part1 ...
-func Part1(input []string) (int, error) {
-	start := time.Now()
-
-	// Check input length
-	if len(input) != 2 {
-		return 0, fmt.Errorf("input should only have two lines")
+	// Joining the digits keeps the span's position so errors still point at
+	// the start of the numbers
+	joined := value
+	joined.Text = ""
+	for _, field := range value.Fields() {
+		joined.Text += field.Text
 	}
-	logger.Debugln("First line:", input[0])
-	logger.Debugln("Second line:", input[1])
-
-	// Remove "Time:" from the first line and "Distance:" from the second line
-	timeLine := strings.TrimPrefix(input[0], "Time:")
-	distanceLine := strings.TrimPrefix(input[1], "Distance:")
-
-	// Parse times
-	timeStrings := strings.Fields(timeLine)
-	times := make([]int, len(timeStrings))
-	for i, t := range timeStrings {
-		logger.Debugln("Position:", i, "Time:", t)
-		time, err := strconv.Atoi(t)
-		if err != nil {
-			return 0, err
-		}
-		times[i] = time
+	long, err := joined.Int()
+	if err != nil {
+		return nil, 0, err
 	}
+	return numbers, long, nil
+}
 
-	// Parse distances
-	distanceStrings := strings.Fields(distanceLine)
-	distances := make([]int, len(distanceStrings))
-	for i, d := range distanceStrings {
-		logger.Debugln("Position:", i, "Distance:", d)
-		distance, err := strconv.Atoi(d)
-		if err != nil {
-			return 0, err
-		}
-		distances[i] = distance
-	}
+// Part1 multiplies together the number of ways to win each race.
+func Part1(rc *common.RunContext, races Races) (common.Answer, error) {
+	start := time.Now()
 
-	// Calculate winning strategy
 	totalWays := 1
-	for i := 0; i < len(times); i++ {
-		ways := 0
-		for j := 0; j < times[i]; j++ {
-			if ((times[i] - j) * j) > distances[i] {
-				ways++
-			}
+	for _, race := range races.Races {
+		ways, err := countWays(rc, race)
+		if err != nil {
+			return common.Answer{}, err
 		}
 		totalWays *= ways
-		logger.Debugln("Ways:", ways)
-		logger.Debugln("Total ways:", totalWays)
+		rc.Logger.Debugln("Race:", race, "Ways:", ways, "Total ways:", totalWays)
 	}
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return totalWays, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(totalWays), nil
 }
 
-// Part2 ...
-func Part2(input []string) (int, error) {
+// Part2 counts the number of ways to win the one long race.
+func Part2(rc *common.RunContext, races Races) (common.Answer, error) {
 	start := time.Now()
 
-	// Check input length
-	if len(input) != 2 {
-		return 0, fmt.Errorf("input should only have two lines")
+	ways, err := countWays(rc, races.Long)
+	if err != nil {
+		return common.Answer{}, err
 	}
 
-	// Swap spaces for nothing
-	timeLine := strings.ReplaceAll(input[0], " ", "")
-	distanceLine := strings.ReplaceAll(input[1], " ", "")
-
-	// Remove "Time:" and "Distance:"
-	timeLine = strings.TrimPrefix(timeLine, "Time:")
-	distanceLine = strings.TrimPrefix(distanceLine, "Distance:")
-	logger.Debugln("Real time:", timeLine)
-	logger.Debugln("Real distance:", distanceLine)
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(ways), nil
+}
 
-	// Convert time and distance to integers
-	timeInt, err := strconv.Atoi(timeLine)
+// countWays counts the ways to win a race. With verify turned on in the
+// config the answer is checked by trying every hold time too, which is slow
+// for the long race but leaves no doubt about the rounding.
+func countWays(rc *common.RunContext, race Race) (int, error) {
+	ways, err := waysToWin(race.Time, race.Record)
 	if err != nil {
 		return 0, err
 	}
-	distanceInt, err := strconv.Atoi(distanceLine)
+	if rc.Config.Verify {
+		if slow := bruteForceWays(race.Time, race.Record); slow != ways {
+			return 0, fmt.Errorf("race %v: closed form found %d ways to win but trying every hold time found %d", race, ways, slow)
+		}
+		rc.Logger.Debugln("Verified race:", race)
+	}
+	return ways, nil
+}
+
+// waysToWin counts how many whole milliseconds the button can be held for to
+// beat the record. Holding it for h of t milliseconds travels (t-h)*h, so
+// this counts the h where h*h - t*h + record < 0 rather than trying each one.
+// A hold time which exactly ties the record doesn't count.
+func waysToWin(t, record int) (int, error) {
+	lo, hi, err := mathx.QuadraticBelow(1, -t, record)
 	if err != nil {
 		return 0, err
 	}
+	// The button can only be held for between 0 and t milliseconds
+	lo, hi = max(lo, 0), min(hi, t+1)
+	return max(hi-lo, 0), nil
+}
 
-	// Calculate the number of ways to win
-	waysToWin := 0
-	for i := 0; i < timeInt; i++ {
-		if ((timeInt - i) * i) > distanceInt {
-			waysToWin++
+// bruteForceWays counts the ways to win by trying every hold time, the way
+// the puzzle describes it.
+func bruteForceWays(t, record int) int {
+	ways := 0
+	for hold := 0; hold <= t; hold++ {
+		if (t-hold)*hold > record {
+			ways++
 		}
 	}
+	return ways
+}
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return waysToWin, nil
+// generateRaces makes random races. Size is the longest a race can last, up
+// to a billion milliseconds. There are four races like the real input, but
+// fewer when they're long, so the one long race of Part 2 and its record
+// still fit in an int. Every record can be beaten.
+func generateRaces(r *rand.Rand, size int) []string {
+	size = min(size, 999_999_999)
+	count := max(1, min(4, 9/len(strconv.Itoa(size))))
+
+	times, records := []string{"Time:"}, []string{"Distance:"}
+	for i := 0; i < count; i++ {
+		t := 1 + r.Intn(size)
+		best := (t / 2) * (t - t/2) // Holding for half the race goes furthest
+		record := 0
+		if best > 0 {
+			record = r.Intn(best)
+		}
+		times = append(times, strconv.Itoa(t))
+		records = append(records, strconv.Itoa(record))
+	}
+	return []string{strings.Join(times, " "), strings.Join(records, " ")}
 }
@@ -0,0 +1,112 @@
+// Package day06 solves the Advent of Code 2023 Day 06 problem.
+package day06
+
+import (
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/check"
+	"jonoricci/advent-of-code-go/common/golden"
+	"math/rand"
+	"testing"
+)
+
+// FuzzParts ensures any sheet of race times and records, however garbled,
+// never makes parsing it or counting the ways to win panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 6)
+}
+
+// TestWaysToWin ensures the closed form agrees with trying every hold time,
+// including records which are tied exactly by some hold times.
+func TestWaysToWin(t *testing.T) {
+	// Holding 10 or 20 of 30ms exactly ties the record of 200mm
+	if ways, err := waysToWin(30, 200); err != nil || ways != 9 {
+		t.Errorf("Expected 9 ways to beat 200mm in 30ms, got %d %v", ways, err)
+	}
+
+	for time := 0; time <= 60; time++ {
+		for record := -5; record <= time*time/4+2; record++ {
+			ways, err := waysToWin(time, record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := bruteForceWays(time, record); ways != want {
+				t.Fatalf("Race of %dms with record %dmm: expected %d ways, got %d", time, record, want, ways)
+			}
+		}
+	}
+}
+
+// TestWaysToWinProperty ensures the closed form agrees with trying every hold
+// time for longer races than TestWaysToWin sweeps through. Records are kept
+// near the best distance possible, where rounding is most likely to go wrong.
+func TestWaysToWinProperty(t *testing.T) {
+	gen := func(r *rand.Rand, size int) Race {
+		time := r.Intn(size * 1000)
+		best := time * time / 4
+		return Race{Time: time, Record: best - r.Intn(size*10) + 2}
+	}
+	shrink := func(race Race) []Race {
+		var smaller []Race
+		for _, time := range check.Int(race.Time) {
+			smaller = append(smaller, Race{Time: time, Record: race.Record})
+		}
+		for _, record := range check.Int(race.Record) {
+			smaller = append(smaller, Race{Time: race.Time, Record: record})
+		}
+		return smaller
+	}
+	slow := func(race Race) (int, error) {
+		return bruteForceWays(race.Time, race.Record), nil
+	}
+	fast := func(race Race) (int, error) {
+		return waysToWin(race.Time, race.Record)
+	}
+	check.Equal(t, check.Config{}, gen, shrink, slow, fast)
+}
+
+// TestVerify ensures both parts pass when checked against brute force.
+func TestVerify(t *testing.T) {
+	rc := common.Background().WithInput("test_input.txt")
+	rc.Config.Verify = true
+
+	races, err := parseRaces(rc, []string{"Time:      7  15   30", "Distance:  9  40  200"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Part1(rc, races); err != nil || !got.Equal(common.Int(288)) {
+		t.Errorf("Expected Part 1 to be 288, got %v %v", got, err)
+	}
+	if got, err := Part2(rc, races); err != nil || !got.Equal(common.Int(71503)) {
+		t.Errorf("Expected Part 2 to be 71503, got %v %v", got, err)
+	}
+}
+
+// TestParseRacesErrors ensures malformed input is reported with its position.
+func TestParseRacesErrors(t *testing.T) {
+	rc := common.Background().WithInput("bad.txt")
+	cases := []struct {
+		input []string
+		want  string
+	}{
+		{[]string{"Time: 7"}, `bad.txt: expected a Time and a Distance line, got 1 lines`},
+		{[]string{"Time: 7 x", "Distance: 9 1"}, `bad.txt:1:9: expected a number, got "x"`},
+		{[]string{"Time: 7 15", "Distance: 9"}, `bad.txt:2:1: expected 2 distances to match the times, got 1`},
+		{[]string{"Time: 7", "Record: 9"}, `bad.txt:2:1: expected "Distance", got "Record"`},
+	}
+	for _, c := range cases {
+		_, err := parseRaces(rc, c.input)
+		if err == nil || err.Error() != c.want {
+			t.Errorf("Expected error %q, got %v", c.want, err)
+		}
+	}
+}
+
+// TestParseRacesTabs ensures numbers separated by tabs are joined into the
+// long race just like those separated by spaces.
+func TestParseRacesTabs(t *testing.T) {
+	races, err := parseRaces(common.Background(), []string{"Time:\t7 \t15", "Distance:\t9\t\t40"})
+	want := Race{Time: 715, Record: 940}
+	if err != nil || races.Long != want {
+		t.Errorf("Expected the long race to be %v, got %v %v", want, races.Long, err)
+	}
+}
@@ -1,84 +1,78 @@
-// Package main solves the Advent of Code 2023 Day 07 problem.
-package main
+// Package day07 solves the Advent of Code 2023 Day 07 problem.
+package day07
 
 import (
 	"fmt"
 	"jonoricci/advent-of-code-go/common"
-	"log"
+	"jonoricci/advent-of-code-go/common/parse"
+	"math/rand"
 	"sort"
-	"strconv"
-	"strings"
 	"time"
-
-	"go.uber.org/zap"
+	"unicode/utf8"
 )
 
-// Global variables for logging and card strength.
+// Global variables for card strength.
 var (
-	logger               *zap.SugaredLogger
 	cardStrengthMap      = map[rune]int{'A': 13, 'K': 12, 'Q': 11, 'J': 10, 'T': 9, '9': 8, '8': 7, '7': 6, '6': 5, '5': 4, '4': 3, '3': 2, '2': 1}
 	jokerCardStrengthMap = map[rune]int{'A': 13, 'K': 12, 'Q': 11, 'T': 10, '9': 9, '8': 8, '7': 7, '6': 6, '5': 5, '4': 4, '3': 3, '2': 2, 'J': 1}
 )
 
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
-
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
+func init() {
+	common.Register(2023, 7, common.SolutionFuncs[[]Hand]{
+		Parser: parseHands,
+		P1:     Part1,
+		P2:     Part2,
+	})
+	common.RegisterGenerator(2023, 7, generateHands)
+}
 
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
-	}
+// Hand is a hand of cards and the bid placed on it.
+type Hand struct {
+	Cards string
+	Bid   int
+}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
+// handPattern matches a line of input such as "32T3K 765".
+var handPattern = parse.MustCompile("{cards} {bid:int}")
 
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-	logger.Infoln("Part 1:", part1)
+// parseHands splits each line of input into a hand and its bid.
+func parseHands(rc *common.RunContext, input []string) ([]Hand, error) {
+	var hands []Hand
+	for _, line := range parse.Lines(rc.Input, input) {
+		m, err := handPattern.Match(line)
+		if err != nil {
+			return nil, err
+		}
 
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalln(err)
+		// Every hand is five cards, each one we know the strength of
+		cards := m.Span("cards")
+		if utf8.RuneCountInString(cards.Text) != 5 {
+			return nil, cards.Errorf(0, "expected a hand of 5 cards, got %q", cards.Text)
+		}
+		for i, card := range cards.Text {
+			if _, ok := cardStrengthMap[card]; !ok {
+				return nil, cards.Errorf(i, "unknown card %q", card)
+			}
+		}
+		hands = append(hands, Hand{Cards: cards.Text, Bid: m.Int("bid")})
 	}
-	logger.Infoln("Part 2:", part2)
+	return hands, nil
 }
 
 // Part1 calculates the total winnings based on Camel Cards game rules
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, input []Hand) (common.Answer, error) {
 	start := time.Now()
-	sum := 0
 	jokerRule := false
-	var err error
 
-	sum, err = processHands(input, jokerRule)
-	if err != nil {
-		return 0, err
-	}
+	sum := processHands(rc, input, jokerRule)
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
 // processHands processes the input hands and calculates the total winnings.
 // It uses the jokerRule to determine whether to apply Part 1 or Part 2 logic.
-func processHands(input []string, jokerRule bool) (int, error) {
+func processHands(rc *common.RunContext, input []Hand, jokerRule bool) int {
 	sum := 0
 
 	type handData struct {
@@ -89,19 +83,10 @@ func processHands(input []string, jokerRule bool) (int, error) {
 	}
 	hands := make([]handData, 0)
 
-	for _, line := range input {
-		// logger.Debugln("Line:", line)
-		splitLine := strings.Split(line, " ")
-		hand := splitLine[0]
-		bid, err := strconv.Atoi(splitLine[1])
-		if err != nil {
-			return 0, fmt.Errorf("failed to convert bid to int: %v", err)
-		}
-
+	for _, h := range input {
 		// Get the hand type and sort the hand left to right by card strength
-		handType, sortedHand := evaluateHand(hand, jokerRule)
-		hands = append(hands, handData{hand, bid, handType, sortedHand})
-		// logger.Debugln("Hand:", hand, "Bid:", bid, "Type:", handType, "Sorted:", sortedHand)
+		handType, sortedHand := evaluateHand(h.Cards, jokerRule)
+		hands = append(hands, handData{h.Cards, h.Bid, handType, sortedHand})
 	}
 
 	// Sort hands based on type and card strength.
@@ -119,7 +104,7 @@ func processHands(input []string, jokerRule bool) (int, error) {
 				return cardStrength(rune(hands[i].hand[k]), jokerRule) > cardStrength(rune(hands[j].hand[k]), jokerRule)
 			}
 		}
-		logger.Warnln("Tie detected between hands:", hands[i].hand, "and", hands[j].hand)
+		rc.Logger.Warnln("Tie detected between hands:", hands[i].hand, "and", hands[j].hand)
 		return false
 	})
 
@@ -127,29 +112,20 @@ func processHands(input []string, jokerRule bool) (int, error) {
 	for i, hd := range hands {
 		rank := len(hands) - i
 		sum += hd.bid * rank
-		logger.Debugln("Ranked Hand:", hd.hand, "Bid:", hd.bid, "Rank:", rank, "Score:", hd.bid*rank, "Sum:", sum)
+		rc.Logger.Debugln("Ranked Hand:", hd.hand, "Bid:", hd.bid, "Rank:", rank, "Score:", hd.bid*rank, "Sum:", sum)
 	}
 
-	return sum, nil
+	return sum
 }
 
 // evaluateHand determines the type and strength of each hand
 func evaluateHand(hand string, jokerRule bool) (int, []string) {
-	counts := make(map[rune]int)
-	jokerCount := 0
+	counts, jokerCount := countCards(hand, jokerRule)
 	var sortedCards []rune
 
-	for _, card := range hand {
-		if jokerRule && card == 'J' {
-			jokerCount++
-		} else {
-			counts[card]++
-		}
-	}
-
 	// Use joker logic for part 2
 	if jokerRule && jokerCount > 0 {
-		return evaluateHandWithJokers(counts, jokerCount)
+		return addJokers(counts, jokerCount)
 	}
 
 	var handType int
@@ -208,6 +184,21 @@ func evaluateHand(hand string, jokerRule bool) (int, []string) {
 	return handType, sortedCardsStr
 }
 
+// countCards counts how many of each card are in a hand. With the joker rule,
+// jokers are counted separately rather than as a card of their own.
+func countCards(hand string, jokerRule bool) (map[rune]int, int) {
+	counts := make(map[rune]int)
+	jokerCount := 0
+	for _, card := range hand {
+		if jokerRule && card == 'J' {
+			jokerCount++
+		} else {
+			counts[card]++
+		}
+	}
+	return counts, jokerCount
+}
+
 // cardStrength returns the strength of a card based on its rank
 func cardStrength(card rune, jokerRule bool) int {
 	if jokerRule {
@@ -218,53 +209,37 @@ func cardStrength(card rune, jokerRule bool) int {
 }
 
 // Part2 calculates the total winnings when using the joker rule
-func Part2(input []string) (int, error) {
+func Part2(rc *common.RunContext, input []Hand) (common.Answer, error) {
 	start := time.Now()
-	sum := 0
 	jokerRule := true
-	var err error
 
-	sum, err = processHands(input, jokerRule)
-	if err != nil {
-		return 0, err
-	}
+	sum := processHands(rc, input, jokerRule)
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
-// evaluteHandWithJokers attempts to find the best possible hand formed by using
-// jokers as wildcards
-func evaluateHandWithJokers(counts map[rune]int, jokerCount int) (int, []string) {
-	bestHandType := 0
-	var bestHand []string
-
-	// Iterate through possible substitutions for Jokers
-	for substitution := range jokerCardStrengthMap {
-		if substitution == 'J' {
-			continue
-		}
-
-		// New counts map for this sub
-		tempCounts := make(map[rune]int)
-		for card, count := range counts {
-			tempCounts[card] = count
+// addJokers finds the best possible hand formed by using jokers as wildcards.
+// Every joker becomes a copy of the most common card, as adding to the biggest
+// group always makes the best hand: four of a kind beats a full house, and
+// three of a kind beats two pair. Ties go to the strongest card, which doesn't
+// change the type but keeps the sorted hand the same each run. A hand of only
+// jokers becomes five aces.
+func addJokers(counts map[rune]int, jokerCount int) (int, []string) {
+	best := 'A'
+	for card, count := range counts {
+		if count > counts[best] || (count == counts[best] && jokerCardStrengthMap[card] > jokerCardStrengthMap[best]) {
+			best = card
 		}
+	}
 
-		// Apply sub
-		tempCounts[substitution] += jokerCount
-
-		// Evaludate hand
-		tempHandType, tempHand := evaluateSimulatedHand(tempCounts)
-
-		// Update best hand if this one is better
-		if tempHandType > bestHandType {
-			bestHandType = tempHandType
-			bestHand = tempHand
-		}
+	tempCounts := make(map[rune]int)
+	for card, count := range counts {
+		tempCounts[card] = count
 	}
+	tempCounts[best] += jokerCount
 
-	return bestHandType, bestHand
+	return evaluateSimulatedHand(tempCounts)
 }
 
 // evaludateSimulatedHand evalues hand type based on counts of cards but without
@@ -328,3 +303,38 @@ func evaluateSimulatedHand(counts map[rune]int) (int, []string) {
 
 	return handType, sortedCardsStr
 }
+
+// maxHands is the most hands generateHands makes. There are 13^5 different
+// hands, and making nearly all of them would take a long time to find the
+// last few not used yet.
+const maxHands = 100_000
+
+// generateHands makes random hands, each with a bid of up to 1000. Size is
+// how many hands there are, up to maxHands, and no hand is dealt twice so
+// every hand has its own rank. Each hand is dealt from a few kinds of card,
+// so every type of hand turns up rather than mostly high cards.
+func generateHands(r *rand.Rand, size int) []string {
+	const cards = "AKQJT98765432"
+	size = min(size, maxHands)
+
+	dealt := make(map[string]bool)
+	var lines []string
+	for len(lines) < size {
+		kinds := 1 + r.Intn(5)
+		hand := make([]byte, 5)
+		for i := range hand {
+			hand[i] = cards[r.Intn(len(cards))]
+			if i >= kinds {
+				hand[i] = hand[r.Intn(kinds)]
+			}
+		}
+		r.Shuffle(len(hand), func(i, j int) { hand[i], hand[j] = hand[j], hand[i] })
+
+		if dealt[string(hand)] {
+			continue
+		}
+		dealt[string(hand)] = true
+		lines = append(lines, fmt.Sprintf("%s %d", hand, 1+r.Intn(1000)))
+	}
+	return lines
+}
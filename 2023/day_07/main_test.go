@@ -1,11 +1,11 @@
-// // Package main solves the Advent of Code 2023 Day 07 problem.
-package main
+// Package day07 solves the Advent of Code 2023 Day 07 problem.
+package day07
 
 import (
 	"fmt"
-	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strings"
+	"jonoricci/advent-of-code-go/common/check"
+	"jonoricci/advent-of-code-go/common/golden"
+	"math/rand"
 	"testing"
 )
 
@@ -65,75 +65,89 @@ func TestEvaluateHand(t *testing.T) {
 	}
 }
 
-// MockInput returns a slice of strings which is the puzzle input.
-func MockInput() []string {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
+// FuzzParts ensures any list of hands and bids, however garbled, never makes
+// parsing it or ranking the hands, with or without jokers, panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 7)
+}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
+// TestAddJokersProperty ensures putting every joker on the most common card
+// makes as good a hand as trying every card in turn.
+func TestAddJokersProperty(t *testing.T) {
+	// Hands from a few kinds of card, with at least one joker, as those are
+	// the ones where the two ways could differ
+	gen := func(r *rand.Rand, size int) string {
+		const cards = "AKQT98765432"
+		kinds := 1 + r.Intn(min(size, len(cards)))
+		hand := []byte{'J'}
+		for len(hand) < 5 {
+			if r.Intn(3) == 0 {
+				hand = append(hand, 'J')
+			} else {
+				hand = append(hand, cards[r.Intn(kinds)])
+			}
+		}
+		r.Shuffle(len(hand), func(i, j int) { hand[i], hand[j] = hand[j], hand[i] })
+		return string(hand)
 	}
-	defer logger.Sync() // Flush any buffered log entries
 
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
+	// Turning a card into a joker or a 2 makes the hand simpler, and can't
+	// go on forever as a joker never turns back
+	shrink := func(hand string) []string {
+		var smaller []string
+		for i, card := range hand {
+			if card != 'J' {
+				smaller = append(smaller, hand[:i]+"J"+hand[i+1:])
+			}
+			if card != 'J' && card != '2' {
+				smaller = append(smaller, hand[:i]+"2"+hand[i+1:])
+			}
+		}
+		return smaller
 	}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	return values
+	slow := func(hand string) (int, error) {
+		handType, _ := evaluateHandWithJokers(countCards(hand, true))
+		return handType, nil
+	}
+	fast := func(hand string) (int, error) {
+		handType, _ := evaluateHand(hand, true)
+		return handType, nil
+	}
+	check.Equal(t, check.Config{}, gen, shrink, slow, fast)
 }
 
-// TestPart1 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart1(t *testing.T) {
-	expectedValues := []int{6440, 249390788}
-	result, err := Part1(MockInput())
-	if err != nil {
-		t.Fatalf("Part1 returned an error: %v", err)
-	}
+// evaluateHandWithJokers attempts to find the best possible hand formed by
+// using jokers as wildcards, by trying each card in turn. It is slower than
+// addJokers but obviously right, so the tests check addJokers against it.
+func evaluateHandWithJokers(counts map[rune]int, jokerCount int) (int, []string) {
+	bestHandType := 0
+	var bestHand []string
+
+	// Iterate through possible substitutions for Jokers
+	for substitution := range jokerCardStrengthMap {
+		if substitution == 'J' {
+			continue
+		}
 
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
+		// New counts map for this sub
+		tempCounts := make(map[rune]int)
+		for card, count := range counts {
+			tempCounts[card] = count
 		}
-	}
 
-	if !found {
-		t.Errorf("Expected Part1 to return %d, got %d", expectedValues, result)
-	}
-}
+		// Apply sub
+		tempCounts[substitution] += jokerCount
 
-// TestPart2 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart2(t *testing.T) {
-	expectedValues := []int{5905, 248750248}
-	result, err := Part2(MockInput())
-	if err != nil {
-		t.Fatalf("Part2 returned an error: %v", err)
-	}
+		// Evaludate hand
+		tempHandType, tempHand := evaluateSimulatedHand(tempCounts)
 
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
+		// Update best hand if this one is better
+		if tempHandType > bestHandType {
+			bestHandType = tempHandType
+			bestHand = tempHand
 		}
 	}
 
-	if !found {
-		t.Errorf("Expected Part2 to return %d, got %d", expectedValues, result)
-	}
+	return bestHandType, bestHand
 }
@@ -1,176 +1,288 @@
-// Package main solves the Advent of Code 2023 Day 08 problem.
-package main
+// Package day08 solves the Advent of Code 2023 Day 08 problem.
+package day08
 
 import (
 	"fmt"
 	"jonoricci/advent-of-code-go/common"
-	"log"
+	"jonoricci/advent-of-code-go/common/graph"
+	"jonoricci/advent-of-code-go/common/mathx"
+	"jonoricci/advent-of-code-go/common/parse"
+	"math/big"
+	"math/rand"
+	"slices"
 	"strings"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
+func init() {
+	common.Register(2023, 8, common.SolutionFuncs[Network]{
+		Parser: parseNetwork,
+		P1:     Part1,
+		P2:     Part2,
+	})
+	common.RegisterKnownGenerator(2023, 8, generateNetwork)
+}
 
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
+// Network is the list of left and right directions to follow and the map of
+// nodes to follow them through.
+type Network struct {
+	Directions []string
+	Nodes      map[string][2]string
+}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
+// parseNetwork splits the input into the directions in the first section and
+// the nodes in the second.
+func parseNetwork(rc *common.RunContext, input []string) (Network, error) {
+	sections := parse.Sections(parse.Lines(rc.Input, input))
+	if len(sections) != 2 {
+		return Network{}, &parse.Error{File: rc.Input, Err: fmt.Errorf("expected directions and nodes separated by a blank line, got %d sections", len(sections))}
 	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
+	if len(sections[0]) != 1 {
+		return Network{}, sections[0][1].Errorf(0, "expected a blank line after the directions")
 	}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	// Execute Part 1
-	part1, err := Part1(values)
+	directions, err := parseDirections(sections[0][0])
 	if err != nil {
-		logger.Warnln(err) // Switched to warn from fatal to pass part 02 test input
+		return Network{}, err
 	}
-	logger.Infoln("Part 1:", part1)
-
-	// Execute Part 2
-	part2, err := Part2(values)
+	nodes, err := parseNodes(sections[1])
 	if err != nil {
-		logger.Fatalln(err)
+		return Network{}, err
 	}
-	logger.Infoln("Part 2:", part2)
+	return Network{Directions: directions, Nodes: nodes}, nil
 }
 
 // Part1 navigates through the puzzle input to count the steps from "AAA" to
 // "ZZZ".
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, network Network) (common.Answer, error) {
 	start := time.Now()
-	sum := 0
 
-	directons := parseDirections(input[0])
-	nodes := parseNodes(input[1:])
+	if _, exists := network.Nodes["AAA"]; !exists {
+		return common.Answer{}, fmt.Errorf("no node AAA to start from")
+	}
+	path := findPath(network, "AAA", func(node string) bool { return node == "ZZZ" })
+	rc.Logger.Debugln("Path:", path)
 
-	sum, err := navigateNodes(directons, nodes)
-	if err != nil {
-		return 0, err
+	// The path visits every place it ever will within its tail and first
+	// loop, so if ZZZ isn't there it can never be reached
+	if len(path.Ends) == 0 {
+		return common.Answer{}, fmt.Errorf("ZZZ can't be reached from AAA")
 	}
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(path.Ends[0]), nil
 }
 
-// parseDirections takes a string of characters and splits each character into
-// a slice
-func parseDirections(input string) []string {
-	return strings.Split(input, "")
+// parseDirections takes a line of 'L' and 'R' characters and splits each
+// character into a slice
+func parseDirections(line parse.Span) ([]string, error) {
+	if line.Text == "" {
+		return nil, line.Errorf(0, "expected directions")
+	}
+	for i, char := range line.Text {
+		if char != 'L' && char != 'R' {
+			return nil, line.Errorf(i, "expected direction L or R, got %q", char)
+		}
+	}
+	return strings.Split(line.Text, ""), nil
 }
 
+// nodePattern matches a line of input such as "AAA = (BBB, CCC)".
+var nodePattern = parse.MustCompile("{node} = ({left}, {right})")
+
 // parseNodes takes a slice of nodes in the format 'AAA = (BBB, CCC)' and splits
 // them into three parts, a node, a left element and a right element which are
-// populated into a map
-func parseNodes(input []string) map[string][2]string {
+// populated into a map. Every left and right element must be a node too.
+func parseNodes(lines []parse.Span) (map[string][2]string, error) {
 	nodes := make(map[string][2]string)
-	for _, line := range input {
-		parts := strings.Split(line, " = ")
-		node := parts[0]
-		// Extract both elements and remove parentheses.
-		elements := strings.Split(parts[1][1:len(parts[1])-1], ", ")
-		nodes[node] = [2]string{elements[0], elements[1]}
-	}
-	return nodes
-}
+	var matches []parse.Match
+	for _, line := range lines {
+		m, err := nodePattern.Match(line)
+		if err != nil {
+			return nil, err
+		}
+		node := m.Text("node")
+		if _, exists := nodes[node]; exists {
+			return nil, line.Errorf(0, "node %s is listed twice", node)
+		}
+		nodes[node] = [2]string{m.Text("left"), m.Text("right")}
+		matches = append(matches, m)
+	}
 
-// navigateNodes will iterate continuously through the directions
-// interacting with the map of nodes to follow through the puzzle input until it
-// finds the "ZZZ" node.
-func navigateNodes(directions []string, nodes map[string][2]string) (int, error) {
-	current := "AAA"
-	steps := 0
-	directionLength := len(directions)
-
-	for current != "ZZZ" {
-		direction := directions[steps%directionLength] // modulo ensures valid index
-		// Steps will exceed directionLength. When moduluo used in a loop it can
-		// cycle over a fixed range
-		logger.Debugln("Current:", current, "Steps:", steps, "Direction:", direction)
-		steps++
-		if node, exists := nodes[current]; exists {
-			if direction == "R" {
-				current = node[1]
-				logger.Debugln("Element:", node[1])
-			} else if direction == "L" {
-				current = node[0]
-				logger.Debugln("Element:", node[0])
+	// Check once every node is known, as nodes can lead to ones listed later
+	for _, m := range matches {
+		for _, name := range []string{"left", "right"} {
+			if _, exists := nodes[m.Text(name)]; !exists {
+				return nil, m.Span(name).Errorf(0, "node %s is not listed", m.Text(name))
 			}
-		} else {
-			return -1, fmt.Errorf("invlaid node: %s", current)
 		}
 	}
-	return steps, nil
+	return nodes, nil
 }
 
 // Part2 navigates through the puzzle input to count the steps using the Ghost
 // method of navigation, which is to start simultaneously on all nodes ending
 // in A and navigate through all of them simultaneously where the result is all
 // nodes are on a step where each node ends in Z.
-func Part2(input []string) (int, error) {
+func Part2(rc *common.RunContext, network Network) (common.Answer, error) {
 	start := time.Now()
 
-	directions := parseDirections(input[0])
-	nodes := parseNodes(input[1:])
-
-	// Find individual path lengths
-	var pathLengths []int
-	for node := range nodes {
+	// Work out where each ghost's path ends up looping. Sorting the start
+	// nodes keeps the logs and any error the same each run.
+	var starts []string
+	for node := range network.Nodes {
 		if strings.HasSuffix(node, "A") {
-			length, err := navigateIndividualPath(node, "Z", directions, nodes)
-			if err != nil {
-				return 0, err
-			}
-			pathLengths = append(pathLengths, length)
+			starts = append(starts, node)
 		}
 	}
+	slices.Sort(starts)
 
-	// Calculate LCM of path lengths
-	lcm, err := calculateLCM(pathLengths)
+	var paths []ghostPath
+	for _, node := range starts {
+		path := findPath(network, node, func(node string) bool { return strings.HasSuffix(node, "Z") })
+		rc.Logger.Debugln("Path:", path)
+		if len(path.Ends) == 0 {
+			return common.Answer{}, fmt.Errorf("ghost starting at %s never reaches a node ending in Z", node)
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return common.Answer{}, fmt.Errorf("no nodes end in A")
+	}
+
+	steps, err := alignPaths(paths)
 	if err != nil {
-		return 0, err
+		return common.Answer{}, err
+	}
+
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.BigInt(steps), nil
+}
+
+// ghostState is where a ghost is: the node it is on and which of the
+// directions it follows next. Once a state comes round again the ghost
+// repeats the same steps forever.
+type ghostState struct {
+	node string
+	next int // Index into the directions
+}
+
+// ghostPath describes the steps from a start node. After Tail steps the path
+// goes round a loop of Length steps forever. Ends lists every step, in order,
+// before Tail+Length at which the path is on an end node, which covers every
+// end it will ever reach.
+type ghostPath struct {
+	Start  string
+	Tail   int
+	Length int
+	Ends   []int
+}
+
+// findPath follows the directions from start to find where the path loops and
+// which steps land on an end node. The network's nodes must all be known,
+// which parseNodes checks.
+func findPath(network Network, start string, isEnd func(node string) bool) ghostPath {
+	step := func(s ghostState) ghostState {
+		node := network.Nodes[s.node][directionIndex(network.Directions[s.next])]
+		return ghostState{node: node, next: (s.next + 1) % len(network.Directions)}
+	}
+
+	path := ghostPath{Start: start}
+	path.Tail, path.Length = graph.FindLoop(ghostState{node: start}, step)
+
+	// Walk the path again, now the loop is known, noting the end nodes
+	state := ghostState{node: start}
+	for i := 0; i < path.Tail+path.Length; i++ {
+		if isEnd(state.node) {
+			path.Ends = append(path.Ends, i)
+		}
+		state = step(state)
 	}
+	return path
+}
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return lcm, nil
+// at reports whether the path is on an end node after the given steps.
+func (p ghostPath) at(steps int) bool {
+	if steps >= p.Tail {
+		// Steps within the loop wrap round to the first time through it
+		steps = p.Tail + (steps-p.Tail)%p.Length
+	}
+	_, found := slices.BinarySearch(p.Ends, steps)
+	return found
 }
 
-// navigateIndividualPath navigates from a given start node to an end node (that
-// ends with 'Z').
-func navigateIndividualPath(startNode, endSuffix string, directions []string, nodes map[string][2]string) (int, error) {
-	steps := 0
-	directionLength := len(directions)
-	currentNode := startNode
+// alignPaths finds the fewest steps after which every path is on an end node
+// at once. Each path's ends within its loop come round again every Length
+// steps, so once every path is in its loop the steps must satisfy one
+// congruence per path, steps ≡ end (mod Length), which the Chinese Remainder
+// Theorem combines. Paths with several ends in their loop give a choice of
+// congruence, and each combination is tried, dropping any as soon as they
+// contradict each other. Unlike taking the LCM of the first ends this doesn't
+// rely on the input being built so each path loops back neatly to its start.
+func alignPaths(paths []ghostPath) (*big.Int, error) {
+	// Before the longest tail there are only so many steps to check, and any
+	// which line up are sooner than anything the loops can give
+	longest := slices.MaxFunc(paths, func(a, b ghostPath) int { return a.Tail - b.Tail })
+	for _, end := range longest.Ends {
+		if end >= longest.Tail {
+			break
+		}
+		aligned := true
+		for _, p := range paths {
+			aligned = aligned && p.at(end)
+		}
+		if aligned {
+			return big.NewInt(int64(end)), nil
+		}
+	}
 
-	for !strings.HasSuffix(currentNode, endSuffix) {
-		direction := directions[steps%directionLength]
-		nextNode := nodes[currentNode][directionIndex(direction)]
-		if nextNode == "" {
-			return -1, fmt.Errorf("invalid node: %s", currentNode)
+	// Otherwise every path is in its loop, where each of its ends gives a
+	// choice of congruence. Ends sharing a residue give the same one, so are
+	// only tried once.
+	choices := make([][]mathx.Congruence, len(paths))
+	for i, p := range paths {
+		seen := make(map[int]bool)
+		for _, end := range p.Ends {
+			if end < p.Tail || seen[end%p.Length] {
+				continue
+			}
+			seen[end%p.Length] = true
+			choices[i] = append(choices[i], mathx.Congruence{Residue: end % p.Length, Modulus: p.Length})
 		}
-		currentNode = nextNode
-		steps++
 	}
-	return steps, nil
+
+	// Try each choice of end, folding in one path's congruence at a time so
+	// a choice contradicting those before it is dropped straight away
+	var best *big.Int
+	var choose func(i int, residue, modulus *big.Int)
+	choose = func(i int, residue, modulus *big.Int) {
+		if i < len(paths) {
+			for _, c := range choices[i] {
+				if r, m, err := mathx.BigMerge(residue, modulus, c); err == nil {
+					choose(i+1, r, m)
+				}
+			}
+			return
+		}
+
+		// The first matching step at or after the longest tail
+		steps := new(big.Int).Sub(big.NewInt(int64(longest.Tail)), residue)
+		steps.Add(steps, new(big.Int).Sub(modulus, big.NewInt(1)))
+		steps.Div(steps, modulus)
+		if steps.Sign() < 0 {
+			steps.SetInt64(0)
+		}
+		steps.Mul(steps, modulus).Add(steps, residue)
+		if best == nil || steps.Cmp(best) < 0 {
+			best = steps
+		}
+	}
+	choose(0, big.NewInt(0), big.NewInt(1))
+
+	if best == nil {
+		return nil, fmt.Errorf("%w: the ghosts are never all on nodes ending in Z at the same time", mathx.ErrNoSolution)
+	}
+	return best, nil
 }
 
 // directionIndex converts a direction character ('L' or 'R') into an index (0 or 1).
@@ -181,27 +293,97 @@ func directionIndex(direction string) int {
 	return 0
 }
 
-// calculateLCM calculates the least common multiple of a slice of integers.
-func calculateLCM(numbers []int) (int, error) {
-	if len(numbers) == 0 {
-		return 0, fmt.Errorf("empty slice provided")
-	}
-	lcm := numbers[0]
-	for _, num := range numbers[1:] {
-		lcm = lcmTwoNumbers(lcm, num)
+// ghostPrimes are how many times round the directions each ghost's loop takes
+// in a generated network. As they're prime, the ghosts only line up once every
+// loop length has been multiplied together.
+var ghostPrimes = []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73, 79, 83, 89, 97}
+
+// nameLetters are the letters generated node names are made of. Leaving out A
+// and Z means only the starts and ends chosen for the ghosts end in them.
+const nameLetters = "BCDEFGHIJKLMNOPQRSTUVWXY"
+
+// nodeName returns the nth name made of width letters.
+func nodeName(n, width int) string {
+	name := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		name[i] = nameLetters[n%len(nameLetters)]
+		n /= len(nameLetters)
 	}
-	return lcm, nil
+	return string(name)
 }
 
-// lcmTwoNumbers calculates the LCM of two numbers.
-func lcmTwoNumbers(a, b int) int {
-	return a * b / gcdTwoNumbers(a, b)
-}
+// generateNetwork makes a random network shaped like the real ones. Size is
+// how many directions there are. There are up to six ghosts, the first
+// starting at AAA and ending at ZZZ so Part 1 has an answer. Each ghost's
+// start leads into a loop which is a different prime number of times as long
+// as the directions, ending on its Z node, so Part 1's answer is the size
+// times the first ghost's prime and Part 2's is the size times each of those
+// primes. Every node's other branch goes to a random node, but is never taken.
+func generateNetwork(r *rand.Rand, size int) common.Known {
+	directions := make([]byte, size)
+	for i := range directions {
+		directions[i] = "LR"[r.Intn(2)]
+	}
+
+	ghosts := 1 + r.Intn(6)
+	primes := r.Perm(len(ghostPrimes))[:ghosts]
+
+	// Every node needs its own name, so longer names are used for big
+	// networks
+	count := 0
+	for _, p := range primes {
+		count += ghostPrimes[p] * size
+	}
+	width := 3
+	for capacity := len(nameLetters) * len(nameLetters) * len(nameLetters); capacity < count; capacity *= len(nameLetters) {
+		width++
+	}
+
+	// A link is the branch taken out of a node, on the given step of the
+	// directions
+	type link struct {
+		node, to string
+		step     int
+	}
+	var links []link
+	named := 0
+	for i, p := range primes {
+		start, end := "AAA", "ZZZ"
+		if i > 0 {
+			prefix := nodeName(i, width-1)
+			start, end = prefix+"A", prefix+"Z"
+		}
+
+		loop := make([]string, ghostPrimes[p]*size)
+		for j := range loop {
+			loop[j] = nodeName(named, width)
+			named++
+		}
+		loop[len(loop)-1] = end
 
-// gcdTwoNumbers calculates the greatest common divisor (GCD) of two numbers.
-func gcdTwoNumbers(a, b int) int {
-	for b != 0 {
-		a, b = b, a%b
+		// The ghost reaches loop[j] after j+1 steps
+		links = append(links, link{node: start, to: loop[0], step: 0})
+		for j, node := range loop {
+			links = append(links, link{node: node, to: loop[(j+1)%len(loop)], step: (j + 1) % size})
+		}
+	}
+
+	lines := make([]string, 0, len(links))
+	for _, l := range links {
+		other := links[r.Intn(len(links))].node
+		branches := [2]string{other, other}
+		branches[directionIndex(string(directions[l.step]))] = l.to
+		lines = append(lines, fmt.Sprintf("%s = (%s, %s)", l.node, branches[0], branches[1]))
+	}
+	r.Shuffle(len(lines), func(i, j int) { lines[i], lines[j] = lines[j], lines[i] })
+
+	part2 := size
+	for _, p := range primes {
+		part2 *= ghostPrimes[p]
+	}
+	return common.Known{
+		Lines: append([]string{string(directions), ""}, lines...),
+		Part1: common.Int(ghostPrimes[primes[0]] * size),
+		Part2: common.Int(part2),
 	}
-	return a
 }
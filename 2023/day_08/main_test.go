@@ -1,82 +1,99 @@
-// // Package main solves the Advent of Code 2023 Day 08 problem.
-package main
+// Package day08 solves the Advent of Code 2023 Day 08 problem.
+package day08
 
 import (
 	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strings"
+	"jonoricci/advent-of-code-go/common/golden"
 	"testing"
 )
 
-// MockInput returns a slice of strings which is the puzzle input.
-func MockInput() []string {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
+// FuzzParts ensures any map of directions and nodes, however garbled, never
+// makes parsing it or following it, alone or as the ghosts, panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 8)
+}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
+// TestParseNetworkErrors ensures malformed input is reported with its
+// position rather than panicking.
+func TestParseNetworkErrors(t *testing.T) {
+	rc := common.Background().WithInput("bad.txt")
+	cases := []struct {
+		input []string
+		want  string
+	}{
+		{[]string{"LRX", "", "AAA = (BBB, CCC)"}, `bad.txt:1:3: expected direction L or R, got 'X'`},
+		{[]string{"LR", "", "AAA = (BBB, CCC)", "BBB = BBB, CCC"}, `bad.txt:4:1: expected " = (" after node, got "BBB = BBB, CCC"`},
+		{[]string{"LR", "", "AAA = (BBB)"}, `bad.txt:3:8: expected ", " after left, got "BBB)"`},
+		{[]string{"LR", "", "AAA = (BBB, CCC)", "AAA = (CCC, CCC)"}, `bad.txt:4:1: node AAA is listed twice`},
+		{[]string{"LR", "", "AAA = (BBB, AAA)"}, `bad.txt:3:8: node BBB is not listed`},
+		{[]string{"LR", "AAA = (BBB, CCC)"}, `bad.txt: expected directions and nodes separated by a blank line, got 1 sections`},
 	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
+	for _, c := range cases {
+		_, err := parseNetwork(rc, c.input)
+		if err == nil || err.Error() != c.want {
+			t.Errorf("Expected error %q, got %v", c.want, err)
+		}
 	}
-
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	return values
 }
 
-// TestPart1 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart1(t *testing.T) {
-	expectedValues := []int{2, 6, 20659}
-	result, err := Part1(MockInput())
-	if err != nil {
-		t.Fatalf("Part1 returned an error: %v", err)
+// TestPart2Loops ensures ghosts are lined up correctly when their paths don't
+// loop neatly back to their start, and that impossible networks are reported.
+func TestPart2Loops(t *testing.T) {
+	rc := common.Background().WithInput("loops.txt")
+	cases := []struct {
+		name  string
+		input []string
+		want  string
+	}{
+		// 11A reaches 11Z on even steps and 22A reaches 22Z one step into a
+		// loop of three, so the LCM of the first ends, 2, is wrong
+		{"tails", []string{"L", "", "11A = (11B, 11B)", "11B = (11Z, 11Z)", "11Z = (11B, 11B)", "22A = (22Z, 22Z)", "22Z = (22B, 22B)", "22B = (22C, 22C)", "22C = (22Z, 22Z)"}, "4"},
+		// Both ghosts are on Z together once, before either loop starts
+		{"before loops", []string{"L", "", "11A = (11Z, 11Z)", "11Z = (11B, 11B)", "11B = (11B, 11B)", "22A = (22Z, 22Z)", "22Z = (22Z, 22Z)"}, "1"},
+		{"never aligned", []string{"L", "", "11A = (11Z, 11Z)", "11Z = (11B, 11B)", "11B = (11Z, 11Z)", "22A = (22B, 22B)", "22B = (22Z, 22Z)", "22Z = (22C, 22C)", "22C = (22Z, 22Z)"}, "no number satisfies every congruence: the ghosts are never all on nodes ending in Z at the same time"},
+		{"no Z", []string{"L", "", "11A = (11B, 11B)", "11B = (11B, 11B)"}, "ghost starting at 11A never reaches a node ending in Z"},
 	}
-
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
+	for _, c := range cases {
+		network, err := parseNetwork(rc, c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Part2(rc, network)
+		if err != nil {
+			got = common.String(err.Error())
+		}
+		if got.String() != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, got)
 		}
 	}
+}
 
-	if !found {
-		t.Errorf("Expected Part1 to return %d, got %d", expectedValues, result)
+// TestAlignPathsManyEnds ensures many ghosts each with many ends are lined up
+// without trying every combination of ends, which would never finish.
+func TestAlignPathsManyEnds(t *testing.T) {
+	var paths []ghostPath
+	for i := 0; i < 40; i++ {
+		path := ghostPath{Tail: 0, Length: 12}
+		for end := 1 + i%2; end < 12; end++ {
+			path.Ends = append(path.Ends, end)
+		}
+		paths = append(paths, path)
+	}
+	steps, err := alignPaths(paths)
+	if err != nil || steps.Int64() != 2 {
+		t.Errorf("Expected 2 steps, got %v %v", steps, err)
 	}
 }
 
-// TestPart2 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart2(t *testing.T) {
-	expectedValues := []int{6, 15690466351717}
-	result, err := Part2(MockInput())
+// TestPart1Unreachable ensures a ZZZ which can't be reached is an error
+// rather than an endless loop.
+func TestPart1Unreachable(t *testing.T) {
+	rc := common.Background().WithInput("unreachable.txt")
+	network, err := parseNetwork(rc, []string{"LR", "", "AAA = (BBB, AAA)", "BBB = (AAA, BBB)", "ZZZ = (ZZZ, ZZZ)"})
 	if err != nil {
-		t.Fatalf("Part2 returned an error: %v", err)
+		t.Fatal(err)
 	}
-
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		t.Errorf("Expected Part2 to return %d, got %d", expectedValues, result)
+	if _, err := Part1(rc, network); err == nil || err.Error() != "ZZZ can't be reached from AAA" {
+		t.Errorf("Expected ZZZ to be unreachable, got %v", err)
 	}
 }
@@ -1,182 +1,179 @@
-// Package main solves the Advent of Code 2023 Day 09 problem.
-package main
+// Package day09 solves the Advent of Code 2023 Day 09 problem.
+package day09
 
 import (
 	"jonoricci/advent-of-code-go/common"
-	"log"
+	"jonoricci/advent-of-code-go/common/parse"
+	"math/rand"
 	"strconv"
 	"strings"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
-
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
-
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
-	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-	logger.Infoln("Part 1:", part1)
-
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalln(err)
-	}
-	logger.Infoln("Part 2:", part2)
+func init() {
+	common.Register(2023, 9, common.SolutionFuncs[[][]int]{
+		Parser: parseInputToInts,
+		P1:     Part1,
+		P2:     Part2,
+	})
+	common.RegisterKnownGenerator(2023, 9, generateSequences)
 }
 
 // Part1 takes a sequence of consecutively increasing ints and extrapolates the
 // next value.
-func Part1(input []string) (int, error) {
+func Part1(rc *common.RunContext, sequences [][]int) (common.Answer, error) {
 	start := time.Now()
 	sum := 0
 
-	sequences, err := parseInputToInts(input)
-	if err != nil {
-		return 0, err
-	}
-
+	rows := pascal{}
 	for _, seq := range sequences {
-		sum += extrapolateNextValue(seq)
+		sum += nextValue(seq, rows.row(len(seq)))
 	}
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
 // parseInputToInts takes line input into slices of integers.
-func parseInputToInts(input []string) ([][]int, error) {
+func parseInputToInts(rc *common.RunContext, input []string) ([][]int, error) {
 	var sequences [][]int
-	for _, line := range input {
-		var seq []int
-		for _, numStr := range strings.Fields(line) {
-			num, err := strconv.Atoi(numStr)
-			if err != nil {
-				return nil, err
-			}
-			seq = append(seq, num)
+	for _, line := range parse.Lines(rc.Input, input) {
+		seq, err := line.FieldInts()
+		if err != nil {
+			return nil, err
+		}
+		if len(seq) == 0 {
+			return nil, line.Errorf(0, "expected a sequence of numbers")
 		}
 		sequences = append(sequences, seq)
 	}
 	return sequences, nil
 }
 
-// extrapolateNextValue finds the next value of the sequence by adding the
-// lower sequences together.
-func extrapolateNextValue(seq []int) int {
-	sequences := generateAllSequences(seq)
-	logger.Debugln("Sequences:", sequences)
-	for i := len(sequences) - 2; i >= 0; i-- {
-		lastNum := sequences[i][len(sequences[i])-1]
-		diff := sequences[i+1][len(sequences[i+1])-1]
-		nextVal := lastNum + diff
-		sequences[i] = append(sequences[i], nextVal)
+// pascal holds rows of Pascal's triangle by row number, as every line of input
+// tends to be the same length and so needs the same row.
+type pascal map[int][]int
+
+// row returns the n+1 numbers "n choose k" for k from 0 to n, working them out
+// the first time they're asked for. Each row is found by adding neighbours in
+// the one above, so an overflow for very long sequences wraps the same way as
+// the sums in the difference table do.
+func (p pascal) row(n int) []int {
+	if row, ok := p[n]; ok {
+		return row
 	}
-	logger.Debugln("Final next extrapolated value:", sequences[0][len(sequences[0])-1])
-	return sequences[0][len(sequences[0])-1]
-}
-
-// generateAllSequences generates sequences down to the zero sequence.
-func generateAllSequences(seq []int) [][]int {
-	var sequences [][]int
-	sequences = append(sequences, seq)
-
-	for {
-		lastSeq := sequences[len(sequences)-1]
-		diff := calculateDifferences(lastSeq)
-		sequences = append(sequences, diff)
-		if allZeroes(diff) {
-			break
+	row := []int{1}
+	for i := 1; i <= n; i++ {
+		next := make([]int, i+1)
+		next[0], next[i] = 1, 1
+		for k := 1; k < i; k++ {
+			next[k] = row[k-1] + row[k]
 		}
+		row = next
 	}
-
-	return sequences
+	p[n] = row
+	return row
 }
 
-// calculateDifferences calculates the differences between consecutive numbers
-// in a sequence.
-func calculateDifferences(seq []int) []int {
-	var diffs []int
-	for i := 1; i < len(seq); i++ {
-		diffs = append(diffs, seq[i]-seq[i-1])
+// nextValue finds the next value of a sequence without building the difference
+// table. Working the table back up, the next value of a sequence of n numbers
+// turns out to be each number weighted by a row of Pascal's triangle with
+// alternating signs, ending with a plus:
+//
+//	x[n] = C(n,n-1)x[n-1] - C(n,n-2)x[n-2] + C(n,n-3)x[n-3] - ...
+//
+// For 0 3 6 9 12 15 that gives 6*15 - 15*12 + 20*9 - 15*6 + 6*3 - 1*0 = 18.
+// Row must be row n of Pascal's triangle.
+func nextValue(seq, row []int) int {
+	n := len(seq)
+	value := 0
+	for k, x := range seq {
+		term := row[k] * x
+		if (n-1-k)%2 == 0 {
+			value += term
+		} else {
+			value -= term
+		}
 	}
-	return diffs
+	return value
 }
 
-// allZeroes takes a slice of ints and checks if they are all zeroes
-func allZeroes(seq []int) bool {
-	for _, num := range seq {
-		if num != 0 {
-			return false
+// previousValue finds the value before a sequence the same way as nextValue,
+// starting from the front:
+//
+//	x[-1] = C(n,1)x[0] - C(n,2)x[1] + C(n,3)x[2] - ...
+func previousValue(seq, row []int) int {
+	value := 0
+	for k, x := range seq {
+		term := row[k+1] * x
+		if k%2 == 0 {
+			value += term
+		} else {
+			value -= term
 		}
 	}
-	return true
+	return value
 }
 
 // Part2 takes a sequence of consecutively increasing ints and extrapolates the
 // previous value.
-func Part2(input []string) (int, error) {
+func Part2(rc *common.RunContext, sequences [][]int) (common.Answer, error) {
 	start := time.Now()
 	sum := 0
 
-	sequences, err := parseInputToInts(input)
-	if err != nil {
-		return 0, err
-	}
-
+	rows := pascal{}
 	for _, seq := range sequences {
-		sum += extrapolatePreviousValue(seq)
+		sum += previousValue(seq, rows.row(len(seq)))
 	}
 
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return sum, nil
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(sum), nil
 }
 
-// extrapolatePreviousValue ...
-func extrapolatePreviousValue(seq []int) int {
-	sequences := generateAllSequences(seq)
-	logger.Debugln("Sequences:", sequences)
-
-	// Add a zero at the beginning of the zero sequence
-	zeroSeq := append([]int{0}, sequences[len(sequences)-1]...)
-	sequences[len(sequences)-1] = zeroSeq
+// generateSequences makes random sequences of 21 numbers, like the real input.
+// Size is how many sequences there are. Each is built from its difference
+// table: the first number of up to ten rows is picked at random, and the
+// last of those rows is the same number all the way along. The numbers either
+// side of a sequence come from the same table, giving both answers.
+func generateSequences(r *rand.Rand, size int) common.Known {
+	const length = 21
+	rows := make(pascal)
+
+	lines := make([]string, size)
+	next, previous := 0, 0
+	for i := range lines {
+		// firsts[k] is the first number of the kth row of differences
+		firsts := make([]int, 1+r.Intn(10))
+		for k := range firsts {
+			firsts[k] = r.Intn(41) - 20
+		}
 
-	for i := len(sequences) - 2; i >= 0; i-- {
-		firstNum := sequences[i][0]
-		diff := sequences[i+1][0]
-		prevNum := firstNum - diff
-		sequences[i] = append([]int{prevNum}, sequences[i]...)
+		// Each number is the firsts weighted by a row of Pascal's triangle,
+		// as each row adds up the one below it
+		numbers := make([]string, length)
+		for n := range numbers {
+			row := rows.row(n)
+			value := 0
+			for k, first := range firsts {
+				if k <= n {
+					value += row[k] * first
+				}
+			}
+			numbers[n] = strconv.Itoa(value)
+		}
+		lines[i] = strings.Join(numbers, " ")
+
+		// The next number is weighted by the following row of the triangle,
+		// and the one before the first by alternately adding and taking away
+		// each of the firsts
+		for k, first := range firsts {
+			next += rows.row(length)[k] * first
+			if k%2 == 0 {
+				previous += first
+			} else {
+				previous -= first
+			}
+		}
 	}
-
-	logger.Debugln("Final previous extrapolated value:", sequences[0][0])
-	return sequences[0][0]
+	return common.Known{Lines: lines, Part1: common.Int(next), Part2: common.Int(previous)}
 }
@@ -1,82 +1,124 @@
-// // Package main solves the Advent of Code 2023 Day 09 problem.
-package main
+// Package day09 solves the Advent of Code 2023 Day 09 problem.
+package day09
 
 import (
-	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strings"
+	"jonoricci/advent-of-code-go/common/check"
+	"jonoricci/advent-of-code-go/common/golden"
+	"math/rand"
 	"testing"
 )
 
-// MockInput returns a slice of strings which is the puzzle input.
-func MockInput() []string {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
+// FuzzParts ensures any report of sequences, however garbled, never makes
+// parsing it or extrapolating either way panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 9)
+}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
-	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
+// TestExtrapolateProperty ensures the Pascal's triangle weights extrapolate
+// both ways to the same values as the difference table. Any sequence will do,
+// not just polynomial ones like the puzzle's, as the table always ends in
+// zeroes once it runs out of numbers.
+func TestExtrapolateProperty(t *testing.T) {
+	gen := func(r *rand.Rand, size int) []int {
+		seq := make([]int, 1+r.Intn(size))
+		for i := range seq {
+			seq[i] = r.Intn(2001) - 1000
+		}
+		return seq
 	}
-	defer logger.Sync() // Flush any buffered log entries
-
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
+	shrink := func(seq []int) [][]int {
+		return check.Slice(seq, 1, check.Int)
 	}
+	table := func(seq []int) ([2]int, error) {
+		return [2]int{extrapolatePreviousValue(seq), extrapolateNextValue(seq)}, nil
+	}
+	weights := func(seq []int) ([2]int, error) {
+		row := pascal{}.row(len(seq))
+		return [2]int{previousValue(seq, row), nextValue(seq, row)}, nil
+	}
+	check.Equal(t, check.Config{MaxSize: 25}, gen, shrink, table, weights)
+}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	return values
+// extrapolateNextValue finds the next value of the sequence by adding the
+// lower sequences together. It is slower than nextValue but follows the
+// puzzle step by step, so the tests check nextValue against it.
+func extrapolateNextValue(seq []int) int {
+	sequences := generateAllSequences(seq)
+	for i := len(sequences) - 2; i >= 0; i-- {
+		lastNum := sequences[i][len(sequences[i])-1]
+		diff := lastOrZero(sequences[i+1])
+		nextVal := lastNum + diff
+		sequences[i] = append(sequences[i], nextVal)
+	}
+	return sequences[0][len(sequences[0])-1]
 }
 
-// TestPart1 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart1(t *testing.T) {
-	expectedValues := []int{114, 1938800261}
-	result, err := Part1(MockInput())
-	if err != nil {
-		t.Fatalf("Part1 returned an error: %v", err)
+// lastOrZero returns the last number of a sequence of differences. A sequence
+// of one number has no differences, which means it never changes, so an
+// empty sequence counts as all zeroes.
+func lastOrZero(seq []int) int {
+	if len(seq) == 0 {
+		return 0
 	}
+	return seq[len(seq)-1]
+}
+
+// generateAllSequences generates sequences down to the zero sequence, which
+// may be empty. The first sequence is a copy, so extrapolating never changes
+// the sequence it was given.
+func generateAllSequences(seq []int) [][]int {
+	var sequences [][]int
+	sequences = append(sequences, append([]int(nil), seq...))
 
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
+	for {
+		lastSeq := sequences[len(sequences)-1]
+		diff := calculateDifferences(lastSeq)
+		sequences = append(sequences, diff)
+		if allZeroes(diff) {
 			break
 		}
 	}
 
-	if !found {
-		t.Errorf("Expected Part1 to return %d, got %d", expectedValues, result)
-	}
+	return sequences
 }
 
-// TestPart2 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart2(t *testing.T) {
-	expectedValues := []int{2, 1112}
-	result, err := Part2(MockInput())
-	if err != nil {
-		t.Fatalf("Part2 returned an error: %v", err)
+// calculateDifferences calculates the differences between consecutive numbers
+// in a sequence.
+func calculateDifferences(seq []int) []int {
+	var diffs []int
+	for i := 1; i < len(seq); i++ {
+		diffs = append(diffs, seq[i]-seq[i-1])
 	}
+	return diffs
+}
 
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
+// allZeroes takes a slice of ints and checks if they are all zeroes, which an
+// empty slice is
+func allZeroes(seq []int) bool {
+	for _, num := range seq {
+		if num != 0 {
+			return false
 		}
 	}
+	return true
+}
+
+// extrapolatePreviousValue finds the previous value of the sequence by taking
+// the lower sequences away from the first numbers. Like extrapolateNextValue
+// the tests check previousValue against it.
+func extrapolatePreviousValue(seq []int) int {
+	sequences := generateAllSequences(seq)
 
-	if !found {
-		t.Errorf("Expected Part2 to return %d, got %d", expectedValues, result)
+	// Add a zero at the beginning of the zero sequence
+	zeroSeq := append([]int{0}, sequences[len(sequences)-1]...)
+	sequences[len(sequences)-1] = zeroSeq
+
+	for i := len(sequences) - 2; i >= 0; i-- {
+		firstNum := sequences[i][0]
+		diff := sequences[i+1][0]
+		prevNum := firstNum - diff
+		sequences[i] = append([]int{prevNum}, sequences[i]...)
 	}
+
+	return sequences[0][0]
 }
@@ -1,238 +1,543 @@
-// Package main solves the Advent of Code 2023 Day 10 problem.
-package main
+// Package day10 solves the Advent of Code 2023 Day 10 problem.
+package day10
 
 import (
 	"fmt"
 	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strings"
+	"jonoricci/advent-of-code-go/common/graph"
+	"jonoricci/advent-of-code-go/common/grid"
+	"jonoricci/advent-of-code-go/common/mathx"
+	"jonoricci/advent-of-code-go/common/viz"
+	"math/rand"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// global variable for logging
-var logger *zap.SugaredLogger
+func init() {
+	common.Register(2023, 10, common.SolutionFuncs[Field]{
+		Parser: parseField,
+		P1:     Part1,
+		P2:     Part2,
+	})
+	common.RegisterKnownGenerator(2023, 10, generateField)
+}
+
+// Field is the puzzle input, the tiles along with the loop through S. The loop
+// is found once while parsing, so both parts share it.
+type Field struct {
+	Tiles *grid.Grid[rune]
+	Loop  Loop
+}
 
-func main() {
-	// Load config file
-	cfg, err := common.ReadConfig()
+// parseField converts input into a grid of runes and finds the loop in it.
+func parseField(rc *common.RunContext, input []string) (Field, error) {
+	tiles, err := grid.FromLines(input)
 	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
+		return Field{}, err
 	}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
+	// Debug: Print the grid
+	rc.Logger.Debugln("Grid:\n" + tiles.String())
+
+	loop, err := findLoop(rc, tiles)
 	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
+		return Field{}, err
 	}
-	defer logger.Sync() // Flush any buffered log entries
+	return Field{Tiles: tiles, Loop: loop}, nil
+}
 
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
+// Part1 finds the furthest distance in the loop from the start, which the
+// search out along the pipes from S found while finding the loop.
+func Part1(rc *common.RunContext, field Field) (common.Answer, error) {
+	start := time.Now()
+	loop := field.Loop
+	_, maxDistance := loop.Search.Furthest()
+
+	// Debug: Print the start position
+	rc.Logger.Debugln("Start Position:", loop.Path[0], "Loop length:", len(loop.Path))
+
+	// Going both ways round the loop, the furthest point is half way round
+	// it, so walking the pipes by hand should find a loop twice as long
+	if rc.Config.Verify {
+		path, ok := followPipe(field.Tiles, loop.Path[0], loop.StartPipe)
+		if !ok || len(path) != 2*maxDistance {
+			return common.Answer{}, fmt.Errorf("searching from S found a tile %d away but walking the pipes found a loop of %d tiles", maxDistance, len(path))
+		}
+	}
+	if rc.Frames.Enabled() {
+		emitSearch(rc, field.Tiles, loop.Search.Dist, maxDistance)
 	}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
+	rc.Logger.Infoln("Part 1 took:", time.Since(start))
+	return common.Int(maxDistance), nil
+}
 
-	// Execute Part 1
-	part1, err := Part1(values)
-	if err != nil {
-		logger.Fatalln(err)
+// maxSearchFrames caps how many frames the search is drawn in, as a big loop
+// can be thousands of steps from the start.
+const maxSearchFrames = 100
+
+// emitSearch replays the search out from the start as frames, spreading along
+// the loop a step at a time. Tiles reached are cyan and the newest ones
+// yellow.
+func emitSearch(rc *common.RunContext, tiles *grid.Grid[rune], dist map[grid.Point]int, maxDistance int) {
+	// Group the tiles by how far they are from the start
+	layers := make([][]grid.Point, maxDistance+1)
+	for p, d := range dist {
+		layers[d] = append(layers[d], p)
 	}
-	logger.Infoln("Part 1:", part1)
 
-	// Execute Part 2
-	part2, err := Part2(values)
-	if err != nil {
-		logger.Fatalln(err)
+	every := max(1, len(layers)/maxSearchFrames)
+	var reached []grid.Point
+	for d, layer := range layers {
+		reached = append(reached, layer...)
+		if d%every != 0 && d != maxDistance {
+			continue
+		}
+		rc.Frames.Emit(viz.GridFrame(fmt.Sprintf("Distance %d of %d", d, maxDistance), tiles, viz.Runes,
+			viz.Highlight{Style: viz.Cyan, Points: reached},
+			viz.Highlight{Style: viz.Yellow, Points: layer},
+		))
 	}
-	logger.Infoln("Part 2:", part2)
 }
 
-// Part1 finds the furthest distance in the loop from the start.
-func Part1(input []string) (int, error) {
-	start := time.Now()
+// pipeGraph returns the pipes as a graph, where each tile's neighbours are
+// the tiles its pipe connects to. S is treated as startPipe, the pipe found
+// under it, so it only joins the two tiles of its loop.
+func pipeGraph(rc *common.RunContext, tiles *grid.Grid[rune], startPipe rune) graph.Graph[grid.Point] {
+	return graph.Func[grid.Point](func(p grid.Point) []graph.Edge[grid.Point] {
+		var edges []graph.Edge[grid.Point]
+		// Neighbours off the grid are never visited
+		tiles.Neighbours4(p, func(nextPos grid.Point, _ rune) bool {
+			if isValidNextPos(rc, tiles, startPipe, p, nextPos) {
+				edges = append(edges, graph.Edge[grid.Point]{To: nextPos, Cost: 1})
+			}
+			return true
+		})
+		return edges
+	})
+}
 
-	// Convert input into a grid of runes
-	grid := make([][]rune, len(input))
-	for i, line := range input {
-		// logger.Debugln(line)
-		grid[i] = []rune(line)
-	}
+// pipeOpenings lists the directions each pipe symbol connects to.
+var pipeOpenings = map[rune][]grid.Point{
+	'|': {grid.Up, grid.Down},
+	'-': {grid.Left, grid.Right},
+	'L': {grid.Up, grid.Right},
+	'J': {grid.Up, grid.Left},
+	'7': {grid.Down, grid.Left},
+	'F': {grid.Down, grid.Right},
+}
 
-	// Debug: Print the grid
-	for _, line := range grid {
-		logger.Debugln(string(line))
+// isValidNextPos checks if moving from currPos to nextPos is valid based on the
+// pipe rules. Both pipes must have an opening facing each other, with S being
+// startPipe.
+func isValidNextPos(rc *common.RunContext, tiles *grid.Grid[rune], startPipe rune, currPos, nextPos grid.Point) bool {
+	// Get the symbols of the current and next positions, where off the grid
+	// is never a valid move
+	currSym := tiles.At(currPos)
+	nextSym, ok := tiles.Get(nextPos)
+	if !ok {
+		return false
 	}
 
-	// Find starting position "S"
-	startPos := findStartPos(grid)
-	if startPos == nil {
-		return 0, fmt.Errorf("start position not found")
+	// Define the movement direction
+	dir := nextPos.Sub(currPos)
+	back := dir.Reverse()
+
+	// Debug: Print check of next position validity
+	rc.Logger.Debugln("Checking move from", currPos, "to", nextPos, "Current:", string(currSym), "Next:", string(nextSym))
+
+	// S is whichever pipe findLoop found under it
+	if currSym == 'S' {
+		currSym = startPipe
+	}
+	if nextSym == 'S' {
+		nextSym = startPipe
 	}
+	return hasOpening(currSym, dir) && hasOpening(nextSym, back)
+}
 
-	// Debug: Print the start position
-	logger.Debugln("Start Position:", startPos)
+// hasOpening checks if a pipe symbol connects in the given direction.
+func hasOpening(sym rune, dir grid.Point) bool {
+	for _, opening := range pipeOpenings[sym] {
+		if opening == dir {
+			return true
+		}
+	}
+	return false
+}
 
-	// Walk through the loop to calculate distance
-	distances := make(map[Pos]int)
-	walkLoop(grid, *startPos, distances)
+// Loop is the loop of pipe through the start, in the order it is walked.
+type Loop struct {
+	Path      []grid.Point             // Every tile of the loop, starting with S
+	StartPipe rune                     // The pipe hidden under S
+	Search    *graph.Paths[grid.Point] // How far each tile of the loop is from S
+}
 
-	// Find maximum distance
-	maxDistance := findMaxDistance(distances)
+// findLoop works out which pipe is under S and searches out along the pipes
+// from it. Each pipe S could be is tried in turn, keeping the first where
+// every tile the search reaches joins exactly two others, which only happens
+// when the pipes lead round in a loop back to S.
+func findLoop(rc *common.RunContext, tiles *grid.Grid[rune]) (Loop, error) {
+	startPos, found := grid.Find(tiles, 'S')
+	if !found {
+		return Loop{}, fmt.Errorf("start position not found")
+	}
 
-	logger.Infoln("Part 1 took:", time.Since(start))
-	return maxDistance, nil
+	for _, pipe := range "|-LJ7F" {
+		pipes := pipeGraph(rc, tiles, pipe)
+		// Both of the pipe's openings must join a pipe before it's worth
+		// searching
+		if len(pipes.Neighbours(startPos)) != 2 {
+			continue
+		}
+		search := graph.BFS(pipes, startPos)
+		if path, ok := loopPath(pipes, search); ok {
+			rc.Logger.Debugln("Pipe under S:", string(pipe), "Loop length:", len(path))
+			return Loop{Path: path, StartPipe: pipe, Search: search}, nil
+		}
+	}
+	return Loop{}, fmt.Errorf("no loop of pipe goes through S at %v", startPos)
 }
 
-// Pos is a position on the grid
-type Pos struct {
-	x, y int
+// loopPath puts the tiles a search reached in order round the loop, or
+// returns false if they aren't a loop. The search spreads both ways round the
+// loop from S and meets at the furthest tile, so the loop is the path out to
+// that tile followed by the path back from its other neighbour.
+func loopPath(pipes graph.Graph[grid.Point], search *graph.Paths[grid.Point]) ([]grid.Point, bool) {
+	for p := range search.Dist {
+		if len(pipes.Neighbours(p)) != 2 {
+			return nil, false
+		}
+	}
+
+	furthest, _ := search.Furthest()
+	path := search.PathTo(furthest)
+	var other grid.Point
+	for _, e := range pipes.Neighbours(furthest) {
+		if e.To != path[len(path)-2] {
+			other = e.To
+		}
+	}
+
+	back := search.PathTo(other)
+	for i := len(back) - 1; i >= 1; i-- {
+		path = append(path, back[i])
+	}
+	return path, len(path) == len(search.Dist)
 }
 
-// findStartPos locates starting position "S"
-func findStartPos(grid [][]rune) *Pos {
-	for y, row := range grid {
-		for x, cell := range row {
-			if cell == 'S' {
-				return &Pos{x, y}
+// followPipe walks from the start, as if it were the given pipe, out of its
+// first opening and along the pipes until it arrives back at the start. It
+// returns false if the pipes don't join up into a loop that way. It is a
+// slower check on the loop findLoop finds.
+func followPipe(tiles *grid.Grid[rune], startPos grid.Point, pipe rune) ([]grid.Point, bool) {
+	path := []grid.Point{startPos}
+	dir := pipeOpenings[pipe][0]
+	pos := startPos
+
+	// A loop can't be longer than the grid, which stops a bad guess at the
+	// pipe from walking forever
+	for len(path) <= tiles.Width()*tiles.Height() {
+		pos = pos.Add(dir)
+		if pos == startPos {
+			// Only a loop if it comes back in through the pipe's other opening
+			return path, hasOpening(pipe, dir.Reverse())
+		}
+
+		// The next pipe must open back the way we came
+		sym, ok := tiles.Get(pos)
+		if !ok || !hasOpening(sym, dir.Reverse()) {
+			return nil, false
+		}
+		path = append(path, pos)
+
+		// Leave by the pipe's other opening
+		for _, opening := range pipeOpenings[sym] {
+			if opening != dir.Reverse() {
+				dir = opening
+				break
 			}
 		}
 	}
-	return nil
+	return nil, false
 }
 
-// walkLoop performs DFS to walk through the loop and updates distances.
-func walkLoop(grid [][]rune, startPos Pos, distances map[Pos]int) {
-	var dfs func(p Pos, dist int)
-	dfs = func(p Pos, dist int) {
-		// Debug: Print current position and distance
-		logger.Debugln("Visiting:", p, "Distance:", dist)
-		// If the position is out of bounds, return
-		if p.x < 0 || p.x >= len(grid[0]) || p.y < 0 || p.y >= len(grid) {
-			return
+// Part2 counts the tiles enclosed by the loop.
+func Part2(rc *common.RunContext, field Field) (common.Answer, error) {
+	start := time.Now()
+	tiles, loop := field.Tiles, field.Loop
+	sum := enclosedCount(loop)
+
+	// Scanning across the rows also finds which tiles are inside, which is
+	// needed to draw them
+	if rc.Config.Verify || rc.Frames.Enabled() {
+		inside := insideTiles(tiles, loop)
+		if rc.Config.Verify && len(inside) != sum {
+			return common.Answer{}, fmt.Errorf("area of the loop gave %d tiles inside but scanning the rows found %d", sum, len(inside))
 		}
+		rc.Frames.Emit(loopFrame(tiles, loop, inside))
+	}
 
-		// If the position was visited with a shorter or equal path, return
-		if existingDist, visited := distances[p]; visited && existingDist <= dist {
-			return
-		}
+	rc.Logger.Infoln("Part 2 took:", time.Since(start))
+	return common.Int(sum), nil
+}
+
+// enclosedCount counts the whole tiles inside the loop without looking at
+// them. The shoelace formula gives the area inside the loop, measured between
+// the centres of its tiles:
+//
+//	2A = |Σ (x[i] * y[i+1] - x[i+1] * y[i])|
+//
+// Pick's theorem links that area to the tiles inside (i) and the tiles on the
+// loop (b), as A = i + b/2 - 1, so i = A - b/2 + 1.
+func enclosedCount(loop Loop) int {
+	twiceArea := 0
+	for i, p := range loop.Path {
+		next := loop.Path[(i+1)%len(loop.Path)]
+		twiceArea += p.X*next.Y - next.X*p.Y
+	}
+	twiceArea = mathx.Abs(twiceArea)
+	return (twiceArea-len(loop.Path))/2 + 1
+}
 
-		// Update current position distance
-		distances[p] = dist
+// insideTiles finds the tiles inside the loop by scanning each row from the
+// left, returning them in reading order. Every time the scan crosses the loop it swaps between outside and
+// inside. Only pipes opening upwards (|, L and J) count as a crossing, so a
+// run such as L--7 which goes up and then down again crosses once, while L--J
+// which comes back up doesn't cross at all.
+func insideTiles(tiles *grid.Grid[rune], loop Loop) []grid.Point {
+	onLoop := make(map[grid.Point]bool, len(loop.Path))
+	for _, p := range loop.Path {
+		onLoop[p] = true
+	}
 
-		// Recursively explore adjacent pipes
-		if grid[p.y][p.x] != '.' { // Ignore ground tiles
-			for _, dir := range []Pos{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
-				nextPos := Pos{p.x + dir.x, p.y + dir.y}
-				if isValidNextPos(grid, p, nextPos) {
-					dfs(nextPos, dist+1)
+	var inside []grid.Point
+	for y := 0; y < tiles.Height(); y++ {
+		in := false
+		for x := 0; x < tiles.Width(); x++ {
+			p := grid.Point{X: x, Y: y}
+			if !onLoop[p] {
+				if in {
+					inside = append(inside, p)
 				}
+				continue
+			}
+			sym := tiles.At(p)
+			if sym == 'S' {
+				sym = loop.StartPipe
+			}
+			if hasOpening(sym, grid.Up) {
+				in = !in
 			}
 		}
 	}
+	return inside
+}
 
-	// Start DFS from initial position
-	dfs(startPos, 0)
+// loopLines draws each pipe with box drawing characters so the loop is easier
+// to follow.
+var loopLines = map[rune]string{
+	'|': "│",
+	'-': "─",
+	'L': "└",
+	'J': "┘",
+	'7': "┐",
+	'F': "┌",
 }
 
-// isValidNextPos checks if moving from currPos to nextPos is valid based on the
-// pipe rules.
-func isValidNextPos(grid [][]rune, currPos, nextPos Pos) bool {
-	// Check if next position is out of bounds
-	if nextPos.x < 0 || nextPos.x >= len(grid[0]) || nextPos.y < 0 || nextPos.y >= len(grid) {
-		return false
+// loopFrame draws the grid with the loop in green, the tiles inside it
+// marked I in yellow and everything outside dimmed.
+func loopFrame(tiles *grid.Grid[rune], loop Loop, inside []grid.Point) viz.Frame {
+	onLoop := make(map[grid.Point]bool, len(loop.Path))
+	for _, p := range loop.Path {
+		onLoop[p] = true
+	}
+	isInside := make(map[grid.Point]bool, len(inside))
+	for _, p := range inside {
+		isInside[p] = true
 	}
 
-	// Get the symbols of the current and next positions
-	currSym := grid[currPos.y][currPos.x]
-	nextSym := grid[nextPos.y][nextPos.x]
+	text := func(p grid.Point, sym rune) string {
+		switch {
+		case onLoop[p] && sym != 'S':
+			return loopLines[sym]
+		case isInside[p]:
+			return "I"
+		default:
+			return string(sym)
+		}
+	}
+	title := fmt.Sprintf("Loop of %d tiles with %d inside", len(loop.Path), len(inside))
+	return viz.GridFrame(title, tiles, text,
+		viz.Highlight{Style: viz.Dim, Points: allPoints(tiles)},
+		viz.Highlight{Style: viz.Green, Points: loop.Path},
+		viz.Highlight{Style: viz.Yellow, Points: inside},
+	)
+}
 
-	// Define the movement direction
-	dx := nextPos.x - currPos.x
-	dy := nextPos.y - currPos.y
+// allPoints lists every point on the grid.
+func allPoints(tiles *grid.Grid[rune]) []grid.Point {
+	var points []grid.Point
+	tiles.All(func(p grid.Point, _ rune) bool {
+		points = append(points, p)
+		return true
+	})
+	return points
+}
 
-	// Debug: Print check of next position validity
-	logger.Debugln("Checking move from", currPos, "to", nextPos, "Current:", string(currSym), "Next:", string(nextSym))
-
-	// Check if movement is allowed based on the type of the current and next pipe
-	switch currSym {
-	case '|':
-		return (dy != 0) && (nextSym == '|' || nextSym == '7' || nextSym == 'F' || nextSym == 'S')
-	case '-':
-		return (dx != 0) && (nextSym == '-' || nextSym == 'L' || nextSym == 'J' || nextSym == 'S')
-	case 'L':
-		return ((dy == -1 && nextSym == '|') || (dx == 1 && nextSym == '-')) || nextSym == 'S'
-	case 'J':
-		return ((dy == -1 && nextSym == '|') || (dx == -1 && nextSym == '-')) || nextSym == 'S'
-	case '7':
-		return ((dy == 1 && nextSym == '|') || (dx == -1 && nextSym == '-')) || nextSym == 'S'
-	case 'F':
-		return ((dy == 1 && nextSym == '|') || (dx == 1 && nextSym == '-')) || nextSym == 'S'
-	case 'S':
-		return inferAndCheckPipeAtS(grid, currPos, nextPos)
+// blockSize is how many tiles wide each block of a generated field is. A loop
+// round the edge of a 3x3 block leaves the middle tile inside it.
+const blockSize = 3
+
+// generateField makes a random field of pipes with one loop through S. Size is
+// how many blocks wide and high the field is, and there is a border of one
+// tile round them.
+//
+// The loop is drawn round a random tree of blocks. It follows the edge of
+// each block, but where the tree joins two blocks it crosses over to the
+// other block instead of running down the side they share. As a tree has no
+// cycles this draws a single loop, with the middle of every block and join
+// inside it. Every tile off the loop is a random pipe or ground.
+//
+// The farthest point is half way round the loop, and the tiles inside it are
+// the middle of each block and the two tiles either side of each join.
+func generateField(r *rand.Rand, size int) common.Known {
+	directions := []grid.Point{grid.Up, grid.Down, grid.Left, grid.Right}
+
+	// Grow a tree from a random block, joining a random neighbour of the
+	// tree each time, until it covers about two thirds of the blocks
+	type join struct{ from, to grid.Point }
+	var frontier, joins []join
+	inTree := make(map[grid.Point]bool)
+	addBlock := func(b grid.Point) {
+		inTree[b] = true
+		for _, d := range directions {
+			if n := b.Add(d); n.X >= 0 && n.Y >= 0 && n.X < size && n.Y < size && !inTree[n] {
+				frontier = append(frontier, join{b, n})
+			}
+		}
+	}
+	addBlock(grid.Point{X: r.Intn(size), Y: r.Intn(size)})
+	for len(inTree) < max(1, size*size*2/3) && len(frontier) > 0 {
+		i := r.Intn(len(frontier))
+		j := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		if !inTree[j.to] {
+			joins = append(joins, j)
+			addBlock(j.to)
+		}
 	}
-	return false
-}
 
-func inferAndCheckPipeAtS(grid [][]rune, currPos, nextPos Pos) bool {
-	var up, down, left, right rune
-	if currPos.y > 0 {
-		up = grid[currPos.y-1][currPos.x]
+	// The loop is a set of edges between neighbouring tiles
+	edges := make(map[[2]grid.Point]bool)
+	edge := func(a, b grid.Point) [2]grid.Point {
+		if b.Y < a.Y || (b.Y == a.Y && b.X < a.X) {
+			a, b = b, a
+		}
+		return [2]grid.Point{a, b}
 	}
-	if currPos.y < len(grid)-1 {
-		down = grid[currPos.y+1][currPos.x]
+	side := func(a, b grid.Point, add bool) {
+		step := grid.Point{X: min(max(b.X-a.X, -1), 1), Y: min(max(b.Y-a.Y, -1), 1)}
+		for p := a; p != b; p = p.Add(step) {
+			if add {
+				edges[edge(p, p.Add(step))] = true
+			} else {
+				delete(edges, edge(p, p.Add(step)))
+			}
+		}
 	}
-	if currPos.x > 0 {
-		left = grid[currPos.y][currPos.x-1]
+
+	// corners returns the top left, top right, bottom left and bottom right
+	// tiles of a block
+	corners := func(b grid.Point) (grid.Point, grid.Point, grid.Point, grid.Point) {
+		tl := grid.Point{X: 1 + b.X*blockSize, Y: 1 + b.Y*blockSize}
+		last := blockSize - 1
+		return tl, tl.Add(grid.Point{X: last}), tl.Add(grid.Point{Y: last}), tl.Add(grid.Point{X: last, Y: last})
 	}
-	if currPos.x < len(grid[0])-1 {
-		right = grid[currPos.y][currPos.x+1]
+	for b := range inTree {
+		tl, tr, bl, br := corners(b)
+		side(tl, tr, true)
+		side(bl, br, true)
+		side(tl, bl, true)
+		side(tr, br, true)
+	}
+	for _, j := range joins {
+		// Work from the left or upper block of the two
+		a, b := j.from, j.to
+		if b.X < a.X || b.Y < a.Y {
+			a, b = b, a
+		}
+		_, aTR, aBL, aBR := corners(a)
+		bTL, bTR, bBL, _ := corners(b)
+		if a.Y == b.Y {
+			side(aTR, aBR, false)
+			side(bTL, bBL, false)
+			edges[edge(aTR, bTL)] = true
+			edges[edge(aBR, bBL)] = true
+		} else {
+			side(aBL, aBR, false)
+			side(bTL, bTR, false)
+			edges[edge(aBL, bTL)] = true
+			edges[edge(aBR, bTR)] = true
+		}
 	}
 
-	dx := nextPos.x - currPos.x
-	dy := nextPos.y - currPos.y
+	// Each tile on the loop opens towards the two tiles it's joined to
+	openings := make(map[grid.Point][]grid.Point)
+	for e := range edges {
+		openings[e[0]] = append(openings[e[0]], e[1].Sub(e[0]))
+		openings[e[1]] = append(openings[e[1]], e[0].Sub(e[1]))
+	}
 
-	// Horizontal movement check
-	if dx != 0 {
-		if left == '-' || right == '-' || left == 'J' || right == 'L' || left == '7' || right == 'F' {
-			nextSym := grid[nextPos.y][nextPos.x]
-			return nextSym == '-' || nextSym == 'L' || nextSym == 'J' || nextSym == '7' || nextSym == 'F' || nextSym == 'S'
+	width := size*blockSize + 2
+	tiles := make([][]rune, width)
+	var loop []grid.Point
+	for y := range tiles {
+		tiles[y] = make([]rune, width)
+		for x := range tiles[y] {
+			p := grid.Point{X: x, Y: y}
+			if open, ok := openings[p]; ok {
+				tiles[y][x] = pipeFor(open)
+				loop = append(loop, p)
+			} else {
+				tiles[y][x] = rune("..|-LJ7F"[r.Intn(8)])
+			}
 		}
 	}
 
-	// Vertical movement check
-	if dy != 0 {
-		if up == '|' || down == '|' || up == 'L' || down == 'J' || up == 'F' || down == '7' {
-			nextSym := grid[nextPos.y][nextPos.x]
-			return nextSym == '|' || nextSym == 'L' || nextSym == 'J' || nextSym == '7' || nextSym == 'F' || nextSym == 'S'
+	// A pipe off the loop may lead into S, as in the real input, but only
+	// one, as two could join up into a second loop through S
+	start := loop[r.Intn(len(loop))]
+	tiles[start.Y][start.X] = 'S'
+	leadsIn := false
+	for _, d := range directions {
+		n := start.Add(d)
+		if openings[n] != nil || !hasOpening(tiles[n.Y][n.X], d.Reverse()) {
+			continue
 		}
+		if leadsIn {
+			tiles[n.Y][n.X] = '.'
+		}
+		leadsIn = true
 	}
 
-	return false
+	lines := make([]string, width)
+	for y, row := range tiles {
+		lines[y] = string(row)
+	}
+	return common.Known{
+		Lines: lines,
+		Part1: common.Int(len(loop) / 2),
+		Part2: common.Int(len(inTree) + 2*len(joins)),
+	}
 }
 
-// findMaxDistance finds the maximum distance from the start position.
-func findMaxDistance(distances map[Pos]int) int {
-	maxDist := 0
-	for _, dist := range distances {
-		if dist > maxDist {
-			maxDist = dist
+// pipeFor returns the pipe with the two given openings.
+func pipeFor(open []grid.Point) rune {
+	for _, pipe := range "|-LJ7F" {
+		if hasOpening(pipe, open[0]) && hasOpening(pipe, open[1]) {
+			return pipe
 		}
 	}
-	return maxDist
-}
-
-// Part2 ...
-func Part2(input []string) (int, error) {
-	start := time.Now()
-	sum := 0
-
-	logger.Infoln("Part 2 took:", time.Since(start))
-	return sum, nil
+	panic(fmt.Sprintf("no pipe opens towards %v", open))
 }
@@ -1,82 +1,110 @@
-// // Package main solves the Advent of Code 2023 Day 10 problem.
-package main
+// Package day10 solves the Advent of Code 2023 Day 10 problem.
+package day10
 
 import (
 	"jonoricci/advent-of-code-go/common"
-	"log"
-	"strings"
+	"jonoricci/advent-of-code-go/common/golden"
+	"jonoricci/advent-of-code-go/common/grid"
+	"jonoricci/advent-of-code-go/common/mathx"
+	"slices"
 	"testing"
 )
 
-// MockInput returns a slice of strings which is the puzzle input.
-func MockInput() []string {
-	// Load config file
-	cfg, err := common.ReadConfig()
-	if err != nil {
-		log.Fatalf("Error reading config: %v", err)
-	}
+// FuzzParts ensures any field of pipes, however garbled, never makes finding
+// the loop or the tiles inside it panic.
+func FuzzParts(f *testing.F) {
+	golden.FuzzParts(f, ".", 2023, 10)
+}
 
-	// Initalise logging
-	logger, err = common.InitialiseLogger(cfg)
+// TestVerify ensures searching out from S agrees with walking the pipes by
+// hand, and counting the tiles inside the loop by its area agrees with scanning the
+// rows, for every input.
+func TestVerify(t *testing.T) {
+	puzzle, err := common.Lookup(2023, 10)
 	if err != nil {
-		log.Fatalf("Error initialising logger: %v", err)
+		t.Fatal(err)
 	}
-	defer logger.Sync() // Flush any buffered log entries
+	cfg := common.DefaultConfig()
+	cfg.Verify = true
+	golden.CheckConfig(t, ".", puzzle, cfg)
+}
 
-	// Read puzzle input
-	input, err := common.ReadInputFile(cfg)
-	if err != nil {
-		logger.Fatalln(err)
+// TestFindLoop ensures the pipe under S is worked out from its neighbours and
+// the loop is put in order, each tile next to the one before.
+func TestFindLoop(t *testing.T) {
+	cases := []struct {
+		input []string
+		want  rune
+	}{
+		{[]string{".....", ".S-7.", ".|.|.", ".L-J.", "....."}, 'F'},
+		{[]string{"F-7", "|.S", "L-J"}, '|'},
+		{[]string{"F-7", "|.|", "L-S"}, 'J'},
+		// The pipes to the left and above S don't lead round to it
+		{[]string{".|...", "-S-7.", ".|.|.", ".L-J."}, 'F'},
+	}
+	for _, c := range cases {
+		tiles, err := grid.FromLines(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loop, err := findLoop(common.Background(), tiles)
+		if err != nil || loop.StartPipe != c.want {
+			t.Errorf("Expected S to be %c in %v, got %c %v", c.want, c.input, loop.StartPipe, err)
+			continue
+		}
+		for i, p := range loop.Path {
+			next := loop.Path[(i+1)%len(loop.Path)]
+			if d := next.Sub(p); mathx.Abs(d.X)+mathx.Abs(d.Y) != 1 {
+				t.Errorf("Expected the loop in %v to be in order, got %v", c.input, loop.Path)
+				break
+			}
+		}
 	}
 
-	// Split into lines
-	inputData := strings.Split(strings.Trim(input, " "), "\n")
-	// Remove empty strings from the input
-	values := common.RemoveEmptyStrings(inputData)
-
-	return values
+	tiles, _ := grid.FromLines([]string{"S-.", "|..", "..."})
+	if _, err := findLoop(common.Background(), tiles); err == nil {
+		t.Error("Expected an error when the pipes from S don't loop")
+	}
 }
 
-// TestPart1 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart1(t *testing.T) {
-	expectedValues := []int{4, 8}
-	result, err := Part1(MockInput())
+// TestPipeIntoStart ensures a pipe leading into S from off the loop isn't
+// counted as part of the loop by either part.
+func TestPipeIntoStart(t *testing.T) {
+	rc := common.Background()
+	rc.Config.Verify = true
+	field, err := parseField(rc, []string{
+		".........",
+		"-----S-7.",
+		".....|.|.",
+		".....L-J.",
+		".........",
+	})
 	if err != nil {
-		t.Fatalf("Part1 returned an error: %v", err)
+		t.Fatal(err)
 	}
 
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
-		}
+	if got, err := Part1(rc, field); err != nil || !got.Equal(common.Int(4)) {
+		t.Errorf("Expected Part 1 to be 4, got %v %v", got, err)
 	}
-
-	if !found {
-		t.Errorf("Expected Part1 to return %d, got %d", expectedValues, result)
+	if got, err := Part2(rc, field); err != nil || !got.Equal(common.Int(1)) {
+		t.Errorf("Expected Part 2 to be 1, got %v %v", got, err)
 	}
 }
 
-// TestPart2 ensures function produces the correct result.
-// This works for either test or real puzzle input.
-func TestPart2(t *testing.T) {
-	expectedValues := []int{0, 0}
-	result, err := Part2(MockInput())
+// TestPipeGraph ensures S only joins the two tiles of its loop, not a pipe
+// leading into it from elsewhere.
+func TestPipeGraph(t *testing.T) {
+	tiles, err := grid.FromLines([]string{"-S-7", ".|.|", ".L-J"})
 	if err != nil {
-		t.Fatalf("Part2 returned an error: %v", err)
+		t.Fatal(err)
 	}
-
-	found := false
-	for _, expected := range expectedValues {
-		if result == expected {
-			found = true
-			break
-		}
+	start := grid.Point{X: 1, Y: 0}
+	var got []grid.Point
+	for _, e := range pipeGraph(common.Background(), tiles, 'F').Neighbours(start) {
+		got = append(got, e.To)
 	}
-
-	if !found {
-		t.Errorf("Expected Part2 to return %d, got %d", expectedValues, result)
+	want := []grid.Point{{X: 1, Y: 1}, {X: 2, Y: 0}}
+	if len(got) != len(want) || !slices.Contains(got, want[0]) || !slices.Contains(got, want[1]) {
+		t.Errorf("Expected S to join %v, got %v", want, got)
 	}
 }
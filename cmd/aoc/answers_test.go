@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/golden"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAnswers runs every registered day against each input in its
+// answers.yaml, e.g. "2023/day_07/test_input.txt/part1". The days run at the
+// same time, as they do with "aoc run --all --parallel", so "go test -race"
+// catches any solution still sharing state between runs.
+func TestAnswers(t *testing.T) {
+	for _, year := range common.Years() {
+		for _, day := range common.Days(year) {
+			year, day := year, day // Captured by the parallel subtest
+			puzzle, err := common.Lookup(year, day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Run(fmt.Sprintf("%d/day_%02d", year, day), func(t *testing.T) {
+				t.Parallel()
+				golden.Check(t, common.DayDir("../..", year, day), puzzle)
+			})
+		}
+	}
+}
+
+// TestGenerators runs both parts of every day with a generator against a few
+// of its inputs, which must parse and be solved without error. Where the
+// generator knows the answers from how it made the input they must match.
+// Verify is turned on, so days with a slower way to check their answers use it
+// too.
+func TestGenerators(t *testing.T) {
+	for _, year := range common.Years() {
+		for _, day := range common.Days(year) {
+			year, day := year, day // Captured by the parallel subtest
+			if _, err := common.LookupGenerator(year, day); err != nil {
+				continue
+			}
+			puzzle, err := common.Lookup(year, day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Run(fmt.Sprintf("%d/day_%02d", year, day), func(t *testing.T) {
+				t.Parallel()
+				for _, size := range []int{1, 2, 5, 20} {
+					for seed := int64(1); seed <= 5; seed++ {
+						checkGenerated(t, puzzle, year, day, size, seed)
+					}
+				}
+			})
+		}
+	}
+}
+
+// checkGenerated solves one generated input, failing the test with the input
+// if it can't be or gets an answer the generator knows is wrong.
+func checkGenerated(t *testing.T, puzzle common.Puzzle, year, day, size int, seed int64) {
+	t.Helper()
+	known, err := common.GenerateKnown(year, day, size, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := known.Lines
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cfg := common.DefaultConfig()
+	cfg.Verify = true
+	name := fmt.Sprintf("generated(size=%d,seed=%d)", size, seed)
+	rc := common.NewRunContext(ctx, nil, cfg, year, day).WithInput(name)
+
+	parsed, err := puzzle.Parse(rc, lines)
+	if err != nil {
+		t.Fatalf("%s: %v\n%s", name, err, strings.Join(lines, "\n"))
+	}
+	wants := []common.Answer{known.Part1, known.Part2}
+	for part, solve := range []func(*common.RunContext, any) (common.Answer, error){puzzle.Part1, puzzle.Part2} {
+		got, err := solve(rc.WithPart(part+1), parsed)
+		if err != nil {
+			t.Errorf("%s part %d: %v\n%s", name, part+1, err, strings.Join(lines, "\n"))
+		} else if want := wants[part]; want.String() != "" && !got.Equal(want) {
+			t.Errorf("%s part %d: expected %s, got %s\n%s", name, part+1, want, got, strings.Join(lines, "\n"))
+		}
+	}
+}
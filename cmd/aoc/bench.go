@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/bench"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// benchCommand handles "aoc bench YEAR DAY" and "aoc bench YEAR --all". It
+// times each day's parse and parts over many runs, compares them with the
+// last saved run and saves the results to the history.
+func benchCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	all := fs.Bool("all", false, "benchmark every registered day for the year")
+	root := fs.String("root", ".", "path to the repository root")
+	flags := addConfigFlags(fs, "log-level", "log-format", "log-file")
+	runs := fs.Int("runs", 10, "number of times to run each step")
+	budget := fs.Duration("budget", 10*time.Second, "stop running a step once it has taken this long in total")
+	inputs := addInputFlags(fs)
+	inputs.addGenerateFlags(fs)
+	historyPath := fs.String("history", "", "benchmark history file (default "+bench.HistoryFileName+" in the root)")
+	threshold := fs.Float64("threshold", 0.1, "fraction a median must slow by to count as a regression")
+	minChange := fs.Duration("min-change", 100*time.Microsecond, "ignore regressions smaller than this")
+	save := fs.Bool("save", true, "save the results to the history")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var year int
+	var days []int
+	if *all {
+		if len(positional) != 1 {
+			return fmt.Errorf("usage: aoc bench YEAR --all")
+		}
+		year, err = strconv.Atoi(positional[0])
+		if err != nil {
+			return fmt.Errorf("invalid year %q", positional[0])
+		}
+		days = common.Days(year)
+		if len(days) == 0 {
+			return fmt.Errorf("no solutions registered for %d", year)
+		}
+	} else {
+		if len(positional) != 2 {
+			return fmt.Errorf("usage: aoc bench YEAR DAY")
+		}
+		var day int
+		year, day, err = parseYearDay(positional[0], positional[1])
+		if err != nil {
+			return err
+		}
+		days = []int{day}
+	}
+
+	if *historyPath == "" {
+		*historyPath = filepath.Join(*root, bench.HistoryFileName)
+	}
+	history, err := bench.LoadHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+
+	current := bench.Run{
+		Time:      time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	// Keep going when a day fails so one broken day doesn't hide the rest
+	var errs []error
+	for _, day := range days {
+		results, err := benchDay(ctx, *root, year, day, flags, *inputs, *runs, *budget)
+		current.Results = append(current.Results, results...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	printResults(os.Stdout, current.Results)
+	fmt.Println()
+	if previous, ok := history.Last(); ok {
+		regressions := bench.Compare(previous, current, *threshold, *minChange)
+		printRegressions(os.Stdout, regressions, previous.Time)
+	} else {
+		fmt.Println("No previous run to compare against.")
+	}
+
+	if *save && len(current.Results) > 0 {
+		history.Record(current)
+		if err := history.Save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// benchDay times a day's parse and both parts, each against its selected
+// input. Each input file's parse is timed once. A step which returns an error
+// is left out of the results.
+func benchDay(ctx context.Context, root string, year, day int, flags configFlags, inputFlags inputFlags, runs int, budget time.Duration) ([]bench.Result, error) {
+	run, err := loadDay(ctx, root, year, day, append(flags.overrides(), inputFlags.overrides()...)...)
+	if err != nil {
+		return nil, err
+	}
+	logger := run.logger
+	defer logger.Sync() // Flush any buffered log entries
+
+	inputs, err := run.selectInputs(inputFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []bench.Result
+	var errs []error
+	measure := func(input, step string, fn func() error) {
+		stepLogger := logger.With("input", input)
+		stepLogger.Infof("Benchmarking %d day %02d %s", year, day, step)
+		stats, err := bench.Measure(runs, budget, fn)
+		if err != nil {
+			stepLogger.Errorln(step+":", err)
+			errs = append(errs, fmt.Errorf("%d day %02d %s: %w", year, day, step, err))
+			return
+		}
+		results = append(results, bench.Result{Year: year, Day: day, Input: input, Step: step, Stats: stats})
+	}
+
+	for _, part := range []int{1, 2} {
+		file := inputs.File(part)
+		if file == "" {
+			continue
+		}
+		_, seen := run.parsed[file]
+		in, err := run.parse(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%d day %02d part %d: %w", year, day, part, err))
+			continue
+		}
+
+		// Silence the solution's own logging, it would be repeated every run
+		rc := common.NewRunContext(ctx, nil, run.cfg, year, day).WithInput(file)
+		if !seen {
+			measure(file, "parse", func() error {
+				_, err := run.solution.Parse(rc, in.lines)
+				return err
+			})
+		}
+		solve := run.part(part)
+		partRC := rc.WithPart(part)
+		measure(file, fmt.Sprintf("part%d", part), func() error {
+			_, err := solve(partRC, in.parsed)
+			return err
+		})
+	}
+	return results, errors.Join(errs...)
+}
+
+// printResults writes a table of each step's statistics.
+func printResults(w io.Writer, results []bench.Result) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DAY\tINPUT\tSTEP\tRUNS\tMIN\tMEDIAN\tP95\tALLOCS")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%d/day_%02d\t%s\t%s\t%d\t%v\t%v\t%v\t%d\n",
+			r.Year, r.Day, r.Input, r.Step, r.Runs, r.Min, r.Median, r.P95, r.Allocs)
+	}
+	tw.Flush()
+}
+
+// printRegressions writes a table of the steps which got slower since the
+// previous run.
+func printRegressions(w io.Writer, regressions []bench.Regression, since time.Time) {
+	if len(regressions) == 0 {
+		fmt.Fprintf(w, "No regressions since the run at %s.\n", since.Local().Format(time.DateTime))
+		return
+	}
+
+	fmt.Fprintf(w, "Regressions since the run at %s:\n", since.Local().Format(time.DateTime))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DAY\tINPUT\tSTEP\tPREVIOUS\tMEDIAN\tCHANGE")
+	for _, r := range regressions {
+		fmt.Fprintf(tw, "%d/day_%02d\t%s\t%s\t%v\t%v\t+%.0f%%\n",
+			r.Year, r.Day, r.Input, r.Step, r.Previous, r.Median, r.Change()*100)
+	}
+	tw.Flush()
+}
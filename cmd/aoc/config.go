@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"strconv"
+)
+
+// configFlag is a command line flag which overrides a config key.
+type configFlag struct {
+	name   string
+	key    string
+	usage  string
+	isBool bool // Can be given without a value, e.g. --verify
+}
+
+// knownConfigFlags are the flags commands can add with addConfigFlags.
+var knownConfigFlags = []configFlag{
+	{"log-level", "logLevel", "log level, overrides config", false},
+	{"log-format", "logFormat", "log encoding, console, json or logfmt, overrides config", false},
+	{"log-file", "logFile", "also write logs to this file, overrides config", false},
+	{"base-url", "baseURL", "Advent of Code website, overrides config", false},
+	{"verify", "verify", "check answers against slower versions where a day has them, overrides config", true},
+	{"render", "render", "draw the puzzle where a day can, overrides config", true},
+	{"fps", "fps", "frames per second when drawing the puzzle, overrides config", false},
+}
+
+// configFlags holds the values of the config flags added to a command.
+type configFlags map[configFlag]*string
+
+// addConfigFlags adds the named config flags to a command's flags.
+func addConfigFlags(fs *flag.FlagSet, names ...string) configFlags {
+	flags := make(configFlags)
+	for _, name := range names {
+		for _, f := range knownConfigFlags {
+			if f.name != name {
+				continue
+			}
+			if f.isBool {
+				value := new(string)
+				fs.Var(boolFlag{value}, f.name, f.usage)
+				flags[f] = value
+			} else {
+				flags[f] = fs.String(f.name, "", f.usage)
+			}
+		}
+	}
+	return flags
+}
+
+// boolFlag is a config flag which, like a bool flag, can be given without a
+// value to mean true. It is kept as a string so an unset flag can be told
+// apart from --verify=false.
+type boolFlag struct {
+	value *string
+}
+
+func (f boolFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f boolFlag) Set(s string) error {
+	if _, err := strconv.ParseBool(s); err != nil {
+		return fmt.Errorf("must be true or false")
+	}
+	*f.value = s
+	return nil
+}
+
+// IsBoolFlag tells the flag package the value is optional.
+func (f boolFlag) IsBoolFlag() bool {
+	return true
+}
+
+// overrides returns a config override for each flag which was set.
+func (flags configFlags) overrides() []common.Override {
+	var overrides []common.Override
+	for _, f := range knownConfigFlags {
+		if value, ok := flags[f]; ok && *value != "" {
+			overrides = append(overrides, common.Override{Key: f.key, Value: *value, Source: "flag --" + f.name})
+		}
+	}
+	return overrides
+}
+
+// configCommand handles "aoc config YEAR DAY", printing a day's effective
+// config and where each value came from.
+func configCommand(args []string) error {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	root := fs.String("root", ".", "path to the repository root")
+	flags := addConfigFlags(fs, "log-level", "log-format", "log-file", "base-url", "verify", "render", "fps")
+	inputs := addInputFlags(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: aoc config YEAR DAY")
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+
+	overrides := append(flags.overrides(), inputs.overrides()...)
+	cfg, err := common.LoadConfig(*root, common.DayDir(*root, year, day), overrides...)
+	if err != nil {
+		return err
+	}
+	fmt.Print(cfg.Describe())
+	return nil
+}
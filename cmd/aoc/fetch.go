@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/fetch"
+	"path/filepath"
+)
+
+// fetchCommand handles "aoc fetch YEAR DAY", downloading the day's real input
+// into its directory unless it's already there.
+func fetchCommand(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	root := fs.String("root", ".", "path to the repository root")
+	flags := addConfigFlags(fs, "log-level", "base-url")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: aoc fetch YEAR DAY")
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+
+	dir := common.DayDir(*root, year, day)
+	cfg, err := common.LoadConfig(*root, dir, flags.overrides()...)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	logger, err := common.InitialiseLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("initialising logger: %w", err)
+	}
+	defer logger.Sync() // Flush any buffered log entries
+
+	client := newClient(*root, cfg)
+	path, cached, err := client.Download(year, day, dir)
+	if err != nil {
+		return err
+	}
+
+	if cached {
+		logger.Infoln("Input already downloaded:", path)
+	} else {
+		logger.Infoln("Downloaded input:", path)
+	}
+	return nil
+}
+
+// newClient returns a client for the configured website which records its
+// requests in the repository root, so every run of aoc shares one rate limit.
+func newClient(root string, cfg common.Config) *fetch.Client {
+	client := fetch.NewClient(cfg.BaseURL, cfg.Session)
+	client.LastRequestFile = filepath.Join(root, fetch.LastRequestFileName)
+	return client
+}
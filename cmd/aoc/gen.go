@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"os"
+	"strings"
+)
+
+// genCommand handles "aoc gen YEAR DAY", writing a random input made by the
+// day's registered generator, for testing how a solution copes with inputs
+// bigger than the real one.
+func genCommand(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	size := fs.Int("size", 100, "how big an input to make, which each day's generator describes")
+	seed := fs.Int64("seed", 1, "seed for the input, the same seed makes the same input")
+	out := fs.String("out", "", "file to write the input to (default standard output)")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: aoc gen YEAR DAY")
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+
+	lines, err := common.Generate(year, day, *size, *seed)
+	if err != nil {
+		return err
+	}
+	text := strings.Join(lines, "\n") + "\n"
+	if *out == "" {
+		_, err := fmt.Print(text)
+		return err
+	}
+	return os.WriteFile(*out, []byte(text), 0o644)
+}
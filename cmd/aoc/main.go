@@ -0,0 +1,110 @@
+// Package main is the aoc command which runs any registered Advent of Code
+// solution from the repository root.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"os"
+	"os/signal"
+	"strconv"
+)
+
+// usage is printed when no or an unknown command is given.
+const usage = `Usage: aoc <command> [arguments]
+
+Commands:
+  run YEAR DAY     run a single day's solution
+  run YEAR --all   run every registered day for a year
+  bench YEAR DAY   time a day's parse and parts over many runs
+  bench YEAR --all time every registered day for a year
+  fetch YEAR DAY   download a day's puzzle input
+  new YEAR DAY     create a new day from the templates
+  gen YEAR DAY     write a random input for a day made by its generator
+  tests YEAR DAY   write tests for a day which has none
+  tests YEAR --all write tests for every day of a year which has none
+  submit YEAR DAY PART
+                   submit the answer to a part using the real input
+  config YEAR DAY  show a day's effective config and where each value came from
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Cancel solutions on Ctrl-C so slow ones stop cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(ctx, os.Args[2:])
+	case "bench":
+		err = benchCommand(ctx, os.Args[2:])
+	case "fetch":
+		err = fetchCommand(os.Args[2:])
+	case "new":
+		err = newCommand(os.Args[2:])
+	case "gen":
+		err = genCommand(os.Args[2:])
+	case "tests":
+		err = testsCommand(ctx, os.Args[2:])
+	case "submit":
+		err = submitCommand(ctx, os.Args[2:])
+	case "config":
+		err = configCommand(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	// Every logger is done with by now, so its log file can be closed
+	if closeErr := common.CloseLogFiles(); closeErr != nil && err == nil {
+		err = fmt.Errorf("closing log files: %w", closeErr)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aoc:", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// parseArgs parses flags from args while allowing them to appear between
+// positional arguments, so both "run 2023 --all" and "run --all 2023" work.
+// It returns the positional arguments in order.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseYearDay converts the YEAR and DAY positional arguments to integers.
+func parseYearDay(yearStr, dayStr string) (int, int, error) {
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid year %q", yearStr)
+	}
+	day, err := strconv.Atoi(dayStr)
+	if err != nil || day < 1 || day > 25 {
+		return 0, 0, fmt.Errorf("invalid day %q", dayStr)
+	}
+	return year, day, nil
+}
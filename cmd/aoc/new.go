@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/scaffold"
+	"path/filepath"
+)
+
+// newCommand handles "aoc new YEAR DAY", creating a new day from the templates
+// and adding it to the README and the list of solutions.
+func newCommand(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	root := fs.String("root", ".", "path to the repository root")
+	title := fs.String("title", "", "puzzle title, looked up from the puzzle page with --fetch")
+	download := fs.Bool("fetch", false, "download the puzzle title, example input and real input")
+	flags := addConfigFlags(fs, "log-level", "base-url")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: aoc new YEAR DAY [--title TITLE] [--fetch]")
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	if *title == "" && !*download {
+		return fmt.Errorf("set --title or use --fetch to look it up")
+	}
+
+	dir := common.DayDir(*root, year, day)
+	cfg, err := common.LoadConfig(*root, dir, flags.overrides()...)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	logger, err := common.InitialiseLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("initialising logger: %w", err)
+	}
+	defer logger.Sync() // Flush any buffered log entries
+
+	d := scaffold.Day{Year: year, Day: day, Title: *title}
+	client := newClient(*root, cfg)
+
+	if *download {
+		page, err := client.Get(fmt.Sprintf("/%d/day/%d", year, day))
+		if err != nil {
+			return fmt.Errorf("fetching puzzle page: %w", err)
+		}
+		if d.Title == "" {
+			if d.Title, err = scaffold.ParseTitle(string(page)); err != nil {
+				return err
+			}
+		}
+		if example, ok := scaffold.ParseExample(string(page)); ok {
+			d.Example = example
+		} else {
+			logger.Warnln("No example input found on the puzzle page")
+		}
+	}
+
+	written, err := scaffold.Create(*root, d)
+	for _, path := range written {
+		logger.Infoln("Created:", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := scaffold.UpdateReadme(filepath.Join(*root, "README.md"), d); err != nil {
+		return fmt.Errorf("updating README: %w", err)
+	}
+	if err := scaffold.RegisterImport(filepath.Join(*root, "cmd", "aoc", "solutions.go"), d); err != nil {
+		return fmt.Errorf("registering solution: %w", err)
+	}
+
+	if *download {
+		path, _, err := client.Download(year, day, dir)
+		if err != nil {
+			return fmt.Errorf("downloading input: %w", err)
+		}
+		logger.Infoln("Downloaded input:", path)
+	}
+
+	logger.Infof("Created %d day %02d: %s", year, day, d.Title)
+	return nil
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/viz"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// runCommand handles "aoc run YEAR DAY" and "aoc run YEAR --all".
+func runCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	all := fs.Bool("all", false, "run every registered day for the year")
+	parallel := fs.Bool("parallel", false, "with --all, run the days at the same time")
+	timeout := fs.Duration("timeout", 0, "give up on the run after this long, zero for no limit")
+	root := fs.String("root", ".", "path to the repository root")
+	flags := addConfigFlags(fs, "log-level", "log-format", "log-file", "verify", "render", "fps")
+	inputs := addInputFlags(fs)
+	inputs.addGenerateFlags(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	if *all {
+		if len(positional) != 1 {
+			return fmt.Errorf("usage: aoc run YEAR --all")
+		}
+		year, err := strconv.Atoi(positional[0])
+		if err != nil {
+			return fmt.Errorf("invalid year %q", positional[0])
+		}
+		days := common.Days(year)
+		if len(days) == 0 {
+			return fmt.Errorf("no solutions registered for %d", year)
+		}
+
+		// Keep going when a day fails so one broken day doesn't hide the rest
+		errs := make([]error, len(days))
+		var wg sync.WaitGroup
+		for i, day := range days {
+			if !*parallel {
+				errs[i] = runDay(ctx, *root, year, day, flags, *inputs, false)
+				continue
+			}
+			wg.Add(1)
+			go func(i, day int) {
+				defer wg.Done()
+				errs[i] = runDay(ctx, *root, year, day, flags, *inputs, true)
+			}(i, day)
+		}
+		wg.Wait()
+		return errors.Join(errs...)
+	}
+
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: aoc run YEAR DAY")
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	return runDay(ctx, *root, year, day, flags, *inputs, false)
+}
+
+// inputFlags are the flags choosing which input file each part runs against.
+type inputFlags struct {
+	file string
+	set  string
+	test bool
+
+	generate int   // Size of a generated input to use instead of a file
+	seed     int64 // Seed for the generated input
+}
+
+// addInputFlags adds --input, --set and --test to a command's flags.
+func addInputFlags(fs *flag.FlagSet) *inputFlags {
+	f := &inputFlags{}
+	fs.StringVar(&f.file, "input", "", "input file for both parts, relative to the day's directory")
+	fs.StringVar(&f.set, "set", "", "named input set from the day's config")
+	fs.BoolVar(&f.test, "test", false, `use the "test" input set, short for --set test`)
+	return f
+}
+
+// addGenerateFlags adds --generate and --seed, for commands which can run
+// against a generated input rather than a file.
+func (f *inputFlags) addGenerateFlags(fs *flag.FlagSet) {
+	fs.IntVar(&f.generate, "generate", 0, "use an input of this size made by the day's generator instead of a file")
+	fs.Int64Var(&f.seed, "seed", 1, "with --generate, seed for the input, the same seed makes the same input")
+}
+
+// overrides returns the config override for an --input file.
+func (f inputFlags) overrides() []common.Override {
+	if f.file == "" {
+		return nil
+	}
+	return []common.Override{{Key: "inputFile", Value: f.file, Source: "flag --input"}}
+}
+
+// inputs returns the input file for each part. The selected set comes from
+// the day's config, with no set meaning the config's inputFile, which --input
+// overrides.
+func (f inputFlags) inputs(cfg common.Config) (common.InputSet, error) {
+	if f.file != "" && (f.set != "" || f.test) {
+		return common.InputSet{}, errors.New("--input can't be used with --set or --test")
+	}
+
+	name := f.set
+	if f.test {
+		if name != "" && name != "test" {
+			return common.InputSet{}, errors.New("--test can't be used with --set")
+		}
+		name = "test"
+	}
+	return cfg.SelectInputs(name)
+}
+
+// dayRun holds everything needed to run one day's solution.
+type dayRun struct {
+	solution common.Puzzle
+	cfg      common.Config
+	logger   *zap.SugaredLogger
+	rc       *common.RunContext
+	dir      string
+
+	parsed    map[string]*parsedInput // Keyed by input file
+	generated map[string][]string     // Generated inputs' lines, keyed by name
+}
+
+// parsedInput is an input file's lines and the day's parsed form of them.
+type parsedInput struct {
+	file   string
+	lines  []string
+	parsed any
+}
+
+// loadDay looks up a day's solution and loads its config and logger. The
+// overrides are applied on top of the day's config. The caller must Sync the
+// returned logger.
+func loadDay(ctx context.Context, root string, year, day int, overrides ...common.Override) (*dayRun, error) {
+	solution, err := common.Lookup(year, day)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load config, input paths are relative to the day's directory
+	dir := common.DayDir(root, year, day)
+	cfg, err := common.LoadConfig(root, dir, overrides...)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	// Initalise logging
+	logger, err := common.InitialiseLogger(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("initialising logger: %w", err)
+	}
+	rc := common.NewRunContext(ctx, logger, cfg, year, day)
+	rc.Logger.Debugf("Config for %d day %02d:\n%s", year, day, cfg.Describe())
+
+	return &dayRun{
+		solution:  solution,
+		cfg:       cfg,
+		logger:    rc.Logger,
+		rc:        rc,
+		dir:       dir,
+		parsed:    make(map[string]*parsedInput),
+		generated: make(map[string][]string),
+	}, nil
+}
+
+// parse reads and parses an input file, relative to the day's directory. Each
+// file is only parsed once, so parts sharing an input share the parsed input.
+func (r *dayRun) parse(file string) (*parsedInput, error) {
+	if in, ok := r.parsed[file]; ok {
+		return in, nil
+	}
+
+	// Read puzzle input, unless it was generated
+	path := filepath.Join(r.dir, file)
+	values, generated := r.generated[file]
+	if generated {
+		path = file
+	} else {
+		cfg := r.cfg
+		cfg.InputFile = path
+		var err error
+		if values, err = common.ReadInputLines(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	rc := r.rc.WithInput(file)
+	start := time.Now()
+	parsed, err := r.solution.Parse(rc, values)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	rc.Logger.Infoln("Parse took:", time.Since(start))
+
+	in := &parsedInput{file: file, lines: values, parsed: parsed}
+	r.parsed[file] = in
+	return in, nil
+}
+
+// selectInputs returns the input for each part chosen by the flags. With
+// --generate both parts share an input made by the day's generator, which is
+// kept with the run under a name describing it, to be parsed like a file.
+func (r *dayRun) selectInputs(f inputFlags) (common.InputSet, error) {
+	if f.generate == 0 {
+		return f.inputs(r.cfg)
+	}
+	if f.file != "" || f.set != "" || f.test {
+		return common.InputSet{}, errors.New("--generate can't be used with --input, --set or --test")
+	}
+
+	lines, err := common.Generate(r.rc.Year, r.rc.Day, f.generate, f.seed)
+	if err != nil {
+		return common.InputSet{}, err
+	}
+	name := fmt.Sprintf("generated(size=%d,seed=%d)", f.generate, f.seed)
+	r.generated[name] = lines
+	return common.InputSet{Part1: name, Part2: name}, nil
+}
+
+// partContext returns the RunContext for running a part against an input, so
+// the part and input are added to every line it logs.
+func (r *dayRun) partContext(part int, file string) *common.RunContext {
+	return r.rc.WithInput(file).WithPart(part)
+}
+
+// part returns a day's Part1 or Part2 function.
+func (r *dayRun) part(part int) func(rc *common.RunContext, parsed any) (common.Answer, error) {
+	if part == 1 {
+		return r.solution.Part1
+	}
+	return r.solution.Part2
+}
+
+// runDay runs both parts of a day's solution, each against its selected
+// input, logging the answers. Parallel says other days are running at the
+// same time.
+func runDay(ctx context.Context, root string, year, day int, flags configFlags, inputFlags inputFlags, parallel bool) error {
+	run, err := loadDay(ctx, root, year, day, append(flags.overrides(), inputFlags.overrides()...)...)
+	if err != nil {
+		return err
+	}
+	logger := run.logger
+	defer logger.Sync() // Flush any buffered log entries
+
+	// Play any frames the solution draws. Days running in parallel would
+	// fight over the terminal's keys and mode, so can't be rendered.
+	if run.cfg.Render {
+		if parallel {
+			return fmt.Errorf("%d day %02d: render can't be used with --parallel", year, day)
+		}
+		player := viz.NewPlayer(ctx, os.Stdout, os.Stdin, run.cfg.FPS)
+		defer player.Close()
+		run.rc.Frames = player.Show
+	}
+
+	inputs, err := run.selectInputs(inputFlags)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Advent of Code %d Day %02d", year, day)
+
+	// A failing part is logged rather than stopping the run so the other
+	// part's answer is still shown.
+	var errs []error
+	for _, part := range []int{1, 2} {
+		file := inputs.File(part)
+		if file == "" {
+			logger.Infof("Part %d: no input selected, skipping", part)
+			continue
+		}
+
+		rc := run.partContext(part, file)
+		in, err := run.parse(file)
+		if err != nil {
+			rc.Logger.Errorf("Part %d: %v", part, err)
+			errs = append(errs, fmt.Errorf("%d day %02d part %d: %w", year, day, part, err))
+			continue
+		}
+
+		answer, err := run.part(part)(rc, in.parsed)
+		if err != nil {
+			rc.Logger.Errorf("Part %d: %v", part, err)
+			errs = append(errs, fmt.Errorf("%d day %02d part %d: %w", year, day, part, err))
+			continue
+		}
+		rc.Logger.Infof("Part %d: %v", part, answer)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,16 @@
+package main
+
+// Importing each day registers its solution with the common package.
+import (
+	_ "jonoricci/advent-of-code-go/2015/day_01"
+	_ "jonoricci/advent-of-code-go/2023/day_01"
+	_ "jonoricci/advent-of-code-go/2023/day_02"
+	_ "jonoricci/advent-of-code-go/2023/day_03"
+	_ "jonoricci/advent-of-code-go/2023/day_04"
+	_ "jonoricci/advent-of-code-go/2023/day_05"
+	_ "jonoricci/advent-of-code-go/2023/day_06"
+	_ "jonoricci/advent-of-code-go/2023/day_07"
+	_ "jonoricci/advent-of-code-go/2023/day_08"
+	_ "jonoricci/advent-of-code-go/2023/day_09"
+	_ "jonoricci/advent-of-code-go/2023/day_10"
+)
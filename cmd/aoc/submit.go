@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/fetch"
+	"jonoricci/advent-of-code-go/common/submit"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// submitCommand handles "aoc submit YEAR DAY PART". It solves the part against
+// the day's real input and submits the answer, unless the history shows the
+// answer can't be right.
+func submitCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("submit", flag.ContinueOnError)
+	root := fs.String("root", ".", "path to the repository root")
+	flags := addConfigFlags(fs, "log-level", "base-url")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 3 {
+		return fmt.Errorf("usage: aoc submit YEAR DAY PART")
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	part, err := strconv.Atoi(positional[2])
+	if err != nil || (part != 1 && part != 2) {
+		return fmt.Errorf("invalid part %q", positional[2])
+	}
+
+	// Always answer using the real input, never a test input from config
+	run, err := loadDay(ctx, *root, year, day, flags.overrides()...)
+	if err != nil {
+		return err
+	}
+	logger := run.logger
+	defer logger.Sync() // Flush any buffered log entries
+
+	rc := run.partContext(part, fetch.InputFileName)
+	logger = rc.Logger
+	in, err := run.parse(fetch.InputFileName)
+	if err != nil {
+		return err
+	}
+	result, err := run.part(part)(rc, in.parsed)
+	if err != nil {
+		return fmt.Errorf("part %d: %w", part, err)
+	}
+	answer := result.String()
+	if answer == "" || strings.Contains(answer, "\n") {
+		return fmt.Errorf("answer %q can't be submitted as is, read it and submit by hand", answer)
+	}
+
+	dir := common.DayDir(*root, year, day)
+	history, err := submit.LoadHistory(dir)
+	if err != nil {
+		return err
+	}
+	if err := history.Check(part, answer); err != nil {
+		return fmt.Errorf("not submitting: %w", err)
+	}
+
+	client := newClient(*root, run.cfg)
+
+	logger.Infof("Submitting %d day %02d part %d answer: %s", year, day, part, answer)
+	reply, err := submit.Submit(client, year, day, part, answer)
+	if err != nil {
+		return err
+	}
+
+	history.Record(part, answer, reply, time.Now())
+	if err := history.Save(); err != nil {
+		return fmt.Errorf("saving history: %w", err)
+	}
+
+	switch reply.Outcome {
+	case submit.Correct, submit.AlreadySolved:
+		logger.Infoln("Answer is", reply.Outcome)
+		return nil
+	case submit.Unknown:
+		return fmt.Errorf("unrecognised reply: %s", reply.Message)
+	default:
+		if reply.Wait > 0 {
+			return fmt.Errorf("answer %s, wait %v before trying again", reply.Outcome, reply.Wait)
+		}
+		return fmt.Errorf("answer %s", reply.Outcome)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/scaffold"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// testsCommand handles "aoc tests YEAR DAY" and "aoc tests YEAR --all",
+// writing tests for days which have none.
+func testsCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tests", flag.ContinueOnError)
+	all := fs.Bool("all", false, "write tests for every registered day of the year without them")
+	root := fs.String("root", ".", "path to the repository root")
+	flags := addConfigFlags(fs, "log-level")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var year int
+	var days []int
+	if *all {
+		if len(positional) != 1 {
+			return fmt.Errorf("usage: aoc tests YEAR --all")
+		}
+		if year, err = strconv.Atoi(positional[0]); err != nil {
+			return fmt.Errorf("invalid year %q", positional[0])
+		}
+		days = common.Days(year)
+	} else {
+		if len(positional) != 2 {
+			return fmt.Errorf("usage: aoc tests YEAR DAY")
+		}
+		day := 0
+		if year, day, err = parseYearDay(positional[0], positional[1]); err != nil {
+			return err
+		}
+		days = []int{day}
+	}
+
+	cfg, err := common.LoadConfig(*root, *root, flags.overrides()...)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	logger, err := common.InitialiseLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("initialising logger: %w", err)
+	}
+	defer logger.Sync() // Flush any buffered log entries
+
+	for _, day := range days {
+		dir := common.DayDir(*root, year, day)
+		if _, err := os.Stat(filepath.Join(dir, "main_test.go")); err == nil {
+			if !*all {
+				return fmt.Errorf("%d day %02d already has tests", year, day)
+			}
+			continue
+		}
+		puzzle, err := common.Lookup(year, day)
+		if err != nil {
+			return err
+		}
+
+		// Record answers for inputs without any first, so the tests have
+		// something to check
+		recorded, err := scaffold.RecordAnswers(ctx, dir, puzzle)
+		if err != nil {
+			return fmt.Errorf("recording answers for %d day %02d: %w", year, day, err)
+		}
+		for _, input := range recorded {
+			logger.Infoln("Recorded unverified answers for:", filepath.Join(dir, input))
+		}
+
+		path, err := scaffold.CreateTests(*root, scaffold.Day{Year: year, Day: day})
+		if err != nil {
+			return err
+		}
+		logger.Infoln("Created:", path)
+	}
+	return nil
+}
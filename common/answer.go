@@ -0,0 +1,81 @@
+// Package common provides utility functions shared across the project.
+package common
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// Answer is the result of a puzzle part. Most answers are integers, but some
+// puzzles want a string, an integer too big for int64 or multi-line ASCII art.
+// The zero value is an empty string answer.
+type Answer struct {
+	kind  answerKind
+	i     int64
+	s     string
+	large *big.Int
+}
+
+// answerKind records which of Answer's fields holds the value.
+type answerKind int
+
+const (
+	stringAnswer answerKind = iota
+	intAnswer
+	bigAnswer
+)
+
+// Int returns an integer answer.
+func Int(v int) Answer {
+	return Answer{kind: intAnswer, i: int64(v)}
+}
+
+// Int64 returns an integer answer.
+func Int64(v int64) Answer {
+	return Answer{kind: intAnswer, i: v}
+}
+
+// String returns a string answer.
+func String(v string) Answer {
+	return Answer{kind: stringAnswer, s: v}
+}
+
+// BigInt returns an integer answer which may not fit in an int64. The value is
+// copied so later changes to v don't affect the answer.
+func BigInt(v *big.Int) Answer {
+	if v.IsInt64() {
+		return Int64(v.Int64())
+	}
+	return Answer{kind: bigAnswer, large: new(big.Int).Set(v)}
+}
+
+// String returns the answer as it would be typed into the website.
+func (a Answer) String() string {
+	switch a.kind {
+	case intAnswer:
+		return strconv.FormatInt(a.i, 10)
+	case bigAnswer:
+		return a.large.String()
+	default:
+		return a.s
+	}
+}
+
+// Int64 returns the answer as an int64 and whether it is an integer that fits.
+func (a Answer) Int64() (int64, bool) {
+	switch a.kind {
+	case intAnswer:
+		return a.i, true
+	case bigAnswer:
+		return 0, false
+	default:
+		v, err := strconv.ParseInt(a.s, 10, 64)
+		return v, err == nil
+	}
+}
+
+// Equal reports whether two answers would be typed in the same way, so
+// Int(5) and String("5") are equal.
+func (a Answer) Equal(b Answer) bool {
+	return a.String() == b.String()
+}
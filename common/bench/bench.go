@@ -0,0 +1,69 @@
+// Package bench times a solution's parse and parts over many runs and keeps a
+// history of the results so slower runs can be spotted.
+package bench
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Stats summarises the runs of a single step.
+type Stats struct {
+	Runs   int           `json:"runs"`
+	Min    time.Duration `json:"min"`
+	Median time.Duration `json:"median"`
+	P95    time.Duration `json:"p95"`
+	Allocs uint64        `json:"allocs"` // Average allocations per run
+}
+
+// Measure calls fn up to runs times and returns the timing statistics. Once
+// the runs have taken longer than budget it stops early, so a slow step is
+// only run as many times as fits, but always at least once. A budget of zero
+// means no limit.
+func Measure(runs int, budget time.Duration, fn func() error) (Stats, error) {
+	if runs < 1 {
+		return Stats{}, fmt.Errorf("runs must be at least 1, got %d", runs)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC() // Start each step from a clean heap
+	runtime.ReadMemStats(&before)
+
+	durations := make([]time.Duration, 0, runs)
+	var total time.Duration
+	for len(durations) < runs {
+		start := time.Now()
+		if err := fn(); err != nil {
+			return Stats{}, err
+		}
+		elapsed := time.Since(start)
+		durations = append(durations, elapsed)
+
+		total += elapsed
+		if budget > 0 && total >= budget {
+			break
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return Stats{
+		Runs:   len(durations),
+		Min:    durations[0],
+		Median: percentile(durations, 50),
+		P95:    percentile(durations, 95),
+		Allocs: (after.Mallocs - before.Mallocs) / uint64(len(durations)),
+	}, nil
+}
+
+// percentile returns the nearest rank percentile p of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (len(sorted)*p + 99) / 100 // Rounded up
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
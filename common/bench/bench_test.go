@@ -0,0 +1,113 @@
+package bench
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestPercentile ensures the nearest rank is picked from sorted durations.
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 20; i++ {
+		sorted = append(sorted, time.Duration(i))
+	}
+
+	cases := []struct {
+		p    int
+		want time.Duration
+	}{{0, 1}, {50, 10}, {95, 19}, {100, 20}}
+	for _, c := range cases {
+		if got := percentile(sorted, c.p); got != c.want {
+			t.Errorf("Expected p%d to be %v, got %v", c.p, c.want, got)
+		}
+	}
+	if got := percentile(sorted[:1], 95); got != 1 {
+		t.Errorf("Expected p95 of a single run to be 1, got %v", got)
+	}
+}
+
+// TestMeasure ensures the runs, budget and errors are respected.
+func TestMeasure(t *testing.T) {
+	calls := 0
+	stats, err := Measure(5, 0, func() error { calls++; return nil })
+	if err != nil || calls != 5 || stats.Runs != 5 {
+		t.Errorf("Expected 5 runs, got %d calls, stats %+v, err %v", calls, stats, err)
+	}
+	if stats.Min > stats.Median || stats.Median > stats.P95 {
+		t.Errorf("Expected min <= median <= p95, got %+v", stats)
+	}
+
+	// A slow step stops once the budget is used up
+	calls = 0
+	stats, err = Measure(100, time.Millisecond, func() error {
+		calls++
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != nil || calls != 1 || stats.Runs != 1 {
+		t.Errorf("Expected the budget to stop after 1 run, got %d calls, err %v", calls, err)
+	}
+
+	failure := errors.New("failed")
+	if _, err := Measure(5, 0, func() error { return failure }); !errors.Is(err, failure) {
+		t.Errorf("Expected the step's error, got %v", err)
+	}
+	if _, err := Measure(0, 0, func() error { return nil }); err == nil {
+		t.Errorf("Expected an error for zero runs")
+	}
+}
+
+// TestCompare ensures only steps which got noticeably slower are reported.
+func TestCompare(t *testing.T) {
+	result := func(step string, median time.Duration) Result {
+		return Result{Year: 2023, Day: 5, Input: "input.txt", Step: step, Stats: Stats{Median: median}}
+	}
+	previous := Run{Results: []Result{
+		result("parse", time.Millisecond),
+		result("part1", 10*time.Millisecond),
+		result("part2", time.Second),
+	}}
+	current := Run{Results: []Result{
+		result("parse", 2*time.Millisecond),     // Doubled, but below minChange
+		result("part1", 10500*time.Microsecond), // Within threshold
+		result("part2", 3*time.Second),          // Regressed
+		{Year: 2023, Day: 6, Input: "input.txt", Step: "part1", Stats: Stats{Median: time.Second}},
+	}}
+
+	regressions := Compare(previous, current, 0.1, 5*time.Millisecond)
+	if len(regressions) != 1 || regressions[0].Step != "part2" {
+		t.Fatalf("Expected only part2 to regress, got %+v", regressions)
+	}
+	if change := regressions[0].Change(); change != 2 {
+		t.Errorf("Expected a change of 2, got %v", change)
+	}
+}
+
+// TestHistorySaveLoad ensures runs survive a round trip through the file.
+func TestHistorySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFileName)
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory returned an error: %v", err)
+	}
+	if _, ok := h.Last(); ok {
+		t.Errorf("Expected no runs in a new history")
+	}
+
+	at := time.Date(2023, time.December, 5, 5, 0, 0, 0, time.UTC)
+	h.Record(Run{Time: at, Results: []Result{{Year: 2023, Day: 5, Step: "part1", Stats: Stats{Runs: 3, Median: time.Second}}}})
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory returned an error: %v", err)
+	}
+	last, ok := loaded.Last()
+	if !ok || !last.Time.Equal(at) || len(last.Results) != 1 || last.Results[0].Median != time.Second {
+		t.Errorf("Expected the saved run back, got %+v", loaded.Runs)
+	}
+}
@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// HistoryFileName is the file benchmark runs are kept in, at the repository
+// root.
+const HistoryFileName = "bench_history.json"
+
+// Result is the statistics for one step of a day's solution.
+type Result struct {
+	Year  int    `json:"year"`
+	Day   int    `json:"day"`
+	Input string `json:"input"`
+	Step  string `json:"step"` // parse, part1 or part2
+	Stats
+}
+
+// key identifies the same step across runs.
+func (r Result) key() string {
+	return fmt.Sprintf("%d/%02d/%s/%s", r.Year, r.Day, r.Input, r.Step)
+}
+
+// Run is every result from one invocation of the benchmark.
+type Run struct {
+	Time      time.Time `json:"time"`
+	GoVersion string    `json:"goVersion"`
+	Platform  string    `json:"platform"`
+	Results   []Result  `json:"results"`
+}
+
+// History is every saved benchmark run, oldest first.
+type History struct {
+	Runs []Run `json:"runs"`
+
+	path string
+}
+
+// LoadHistory reads the benchmark history from path. A missing file gets an
+// empty history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Save writes the history back to the file it was loaded from.
+func (h *History) Save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, append(data, '\n'), 0o644)
+}
+
+// Record adds a run to the end of the history.
+func (h *History) Record(run Run) {
+	h.Runs = append(h.Runs, run)
+}
+
+// Last returns the most recent run, or false if there are none.
+func (h *History) Last() (Run, bool) {
+	if len(h.Runs) == 0 {
+		return Run{}, false
+	}
+	return h.Runs[len(h.Runs)-1], true
+}
+
+// Regression is a step whose median got slower than in an earlier run.
+type Regression struct {
+	Result
+	Previous time.Duration // Median in the earlier run
+}
+
+// Change returns how much slower the step got as a fraction, e.g. 0.5 for 50%.
+func (r Regression) Change() float64 {
+	return float64(r.Median-r.Previous) / float64(r.Previous)
+}
+
+// Compare returns the steps in current whose median is more than threshold
+// (a fraction) slower than the same step in previous. Steps which slowed by
+// less than minChange are ignored, as very fast steps are mostly noise. Steps
+// missing from previous are skipped.
+func Compare(previous, current Run, threshold float64, minChange time.Duration) []Regression {
+	before := make(map[string]time.Duration, len(previous.Results))
+	for _, r := range previous.Results {
+		before[r.key()] = r.Median
+	}
+
+	var regressions []Regression
+	for _, r := range current.Results {
+		old, ok := before[r.key()]
+		if !ok || old <= 0 {
+			continue
+		}
+		slower := r.Median - old
+		if slower < minChange || float64(slower) <= float64(old)*threshold {
+			continue
+		}
+		regressions = append(regressions, Regression{Result: r, Previous: old})
+	}
+	return regressions
+}
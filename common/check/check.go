@@ -0,0 +1,201 @@
+// Package check tests that a property holds for many random inputs, such as a
+// fast solution giving the same answer as a slow but obviously correct one.
+//
+// Each day supplies a generator making random valid inputs and, optionally, a
+// shrinker suggesting smaller versions of an input. When an input breaks the
+// property it is shrunk step by step to the smallest input which still does,
+// as a failure on three numbers is far easier to follow than one on three
+// hundred.
+package check
+
+import (
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Gen makes a random input. Size grows over the runs, so the first inputs are
+// small and later ones larger; what it means is up to the generator, such as
+// the length of a list or the largest number in it.
+type Gen[T any] func(r *rand.Rand, size int) T
+
+// Shrink returns smaller versions of an input to try in place of one which
+// breaks the property, simplest first. Each should still be a valid input.
+type Shrink[T any] func(T) []T
+
+// Property returns an error describing how an input breaks it, or nil.
+type Property[T any] func(T) error
+
+// SeedEnv names the environment variable which fixes the seed, to repeat a
+// failed run.
+const SeedEnv = "AOC_CHECK_SEED"
+
+// Config controls how many inputs are tried. The zero Config is ready to use.
+type Config struct {
+	Runs    int   // Inputs to try, 200 if not set
+	MaxSize int   // Size passed to the generator for the last run, 20 if not set
+	Seed    int64 // Seed for the inputs, from SeedEnv or the time if not set
+}
+
+// maxShrinks stops shrinking an input which never stops getting "smaller",
+// which a careless shrinker can cause.
+const maxShrinks = 1000
+
+// Failure describes an input which broke a property.
+type Failure[T any] struct {
+	Input   T     // The smallest failing input found
+	Err     error // How Input broke the property
+	Seed    int64 // The seed which generated the original input
+	Run     int   // Which run, counting from 1, first failed
+	Shrinks int   // How many times the input was shrunk
+}
+
+// Error describes the failure along with how to repeat it.
+func (f *Failure[T]) Error() string {
+	return fmt.Sprintf("failed on run %d, shrunk %d times (repeat with %s=%d):\ninput: %+v\n%v",
+		f.Run, f.Shrinks, SeedEnv, f.Seed, f.Input, f.Err)
+}
+
+// Run checks prop against random inputs from gen and fails the test with the
+// smallest failing input found. Shrink may be nil, in which case failing
+// inputs are reported as generated.
+func Run[T any](t testing.TB, cfg Config, gen Gen[T], shrink Shrink[T], prop Property[T]) {
+	t.Helper()
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failure := find(cfg, gen, shrink, prop); failure != nil {
+		t.Fatal(failure)
+	}
+}
+
+// Equal checks slow and fast give the same result for random inputs from gen,
+// which is the usual way to test an optimisation against the code it
+// replaces. An error from either also counts as a failure, as generated
+// inputs should always be valid.
+func Equal[T any, R comparable](t testing.TB, cfg Config, gen Gen[T], shrink Shrink[T], slow, fast func(T) (R, error)) {
+	t.Helper()
+	Run(t, cfg, gen, shrink, func(input T) error {
+		want, err := slow(input)
+		if err != nil {
+			return fmt.Errorf("slow: %w", err)
+		}
+		got, err := fast(input)
+		if err != nil {
+			return fmt.Errorf("fast: %w", err)
+		}
+		if got != want {
+			return fmt.Errorf("slow gave %v but fast gave %v", want, got)
+		}
+		return nil
+	})
+}
+
+// withDefaults fills in the settings left unset.
+func (cfg Config) withDefaults() (Config, error) {
+	if cfg.Runs <= 0 {
+		cfg.Runs = 200
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 20
+	}
+	if cfg.Seed == 0 {
+		if s := os.Getenv(SeedEnv); s != "" {
+			seed, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return cfg, fmt.Errorf("%s must be a whole number, got %q", SeedEnv, s)
+			}
+			cfg.Seed = seed
+		} else {
+			cfg.Seed = time.Now().UnixNano()
+		}
+	}
+	return cfg, nil
+}
+
+// find runs the property on generated inputs until one fails, then shrinks
+// it. It returns nil if every input passes.
+func find[T any](cfg Config, gen Gen[T], shrink Shrink[T], prop Property[T]) *Failure[T] {
+	r := rand.New(rand.NewSource(cfg.Seed))
+	for run := 1; run <= cfg.Runs; run++ {
+		// Grow the size evenly from 1 up to MaxSize
+		size := 1 + (run-1)*(cfg.MaxSize-1)/max(cfg.Runs-1, 1)
+		input := gen(r, size)
+		if err := try(prop, input); err != nil {
+			failure := &Failure[T]{Input: input, Err: err, Seed: cfg.Seed, Run: run}
+			shrinkFailure(failure, shrink, prop)
+			return failure
+		}
+	}
+	return nil
+}
+
+// shrinkFailure greedily replaces the failing input with the first smaller
+// version which still fails, until none of them do.
+func shrinkFailure[T any](failure *Failure[T], shrink Shrink[T], prop Property[T]) {
+	if shrink == nil {
+		return
+	}
+	for failure.Shrinks < maxShrinks {
+		shrunk := false
+		for _, candidate := range shrink(failure.Input) {
+			if err := try(prop, candidate); err != nil {
+				failure.Input, failure.Err = candidate, err
+				failure.Shrinks++
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			return
+		}
+	}
+}
+
+// try runs the property on one input, turning a panic into an error so the
+// input can still be shrunk and reported.
+func try[T any](prop Property[T], input T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panicked: %v", r)
+		}
+	}()
+	return prop(input)
+}
+
+// Puzzle returns a Gen making inputs with a day's registered generator,
+// parsed by the day's parser, so a property can be checked against inputs
+// shaped like the real one. The test fails if the day has no generator or one
+// of its inputs doesn't parse, as a generator should only make valid input.
+// The day's package must be imported so it is registered.
+func Puzzle[T any](t testing.TB, year, day int) Gen[T] {
+	t.Helper()
+	puzzle, err := common.Lookup(year, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen, err := common.LookupGenerator(year, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc := common.Background().WithInput("generated")
+	return func(r *rand.Rand, size int) T {
+		lines := gen(r, size)
+		parsed, err := puzzle.Parse(rc, lines)
+		if err != nil {
+			t.Fatalf("generated input doesn't parse: %v\n%s", err, strings.Join(lines, "\n"))
+		}
+		input, ok := parsed.(T)
+		if !ok {
+			t.Fatalf("parsed input is %T, expected %T", parsed, input)
+		}
+		return input
+	}
+}
@@ -0,0 +1,140 @@
+package check
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// genInts makes a slice of up to size numbers from 0 to 99.
+func genInts(r *rand.Rand, size int) []int {
+	s := make([]int, r.Intn(size+1))
+	for i := range s {
+		s[i] = r.Intn(100)
+	}
+	return s
+}
+
+// shrinkInts shrinks both the length and the numbers of a slice.
+func shrinkInts(s []int) [][]int {
+	return Slice(s, 0, Int)
+}
+
+// TestFindPasses ensures a property which always holds passes every run.
+func TestFindPasses(t *testing.T) {
+	runs := 0
+	failure := find(Config{Runs: 50, MaxSize: 10, Seed: 1}, genInts, shrinkInts, func(s []int) error {
+		runs++
+		if len(s) > 10 {
+			return fmt.Errorf("size went past 10 with %d numbers", len(s))
+		}
+		return nil
+	})
+	if failure != nil {
+		t.Fatal(failure)
+	}
+	if runs != 50 {
+		t.Errorf("Expected 50 runs, got %d", runs)
+	}
+}
+
+// TestFindShrinks ensures a failing input is shrunk to the smallest one which
+// still fails, and the same seed finds the same failure.
+func TestFindShrinks(t *testing.T) {
+	sumUnder := func(s []int) error {
+		sum := 0
+		for _, n := range s {
+			sum += n
+		}
+		if sum >= 50 {
+			return fmt.Errorf("sum %d is 50 or more", sum)
+		}
+		return nil
+	}
+
+	cfg := Config{Seed: 42}
+	failure := find(cfg.mustDefaults(t), genInts, shrinkInts, sumUnder)
+	if failure == nil {
+		t.Fatal("Expected a failure")
+	}
+	if !reflect.DeepEqual(failure.Input, []int{50}) {
+		t.Errorf("Expected the input to shrink to [50], got %v", failure.Input)
+	}
+	if failure.Shrinks == 0 || failure.Seed != 42 {
+		t.Errorf("Expected shrinks from seed 42, got %d from %d", failure.Shrinks, failure.Seed)
+	}
+
+	again := find(cfg.mustDefaults(t), genInts, shrinkInts, sumUnder)
+	if again == nil || again.Run != failure.Run {
+		t.Errorf("Expected the same seed to fail on run %d again, got %v", failure.Run, again)
+	}
+}
+
+// TestFindPanics ensures a panicking property is reported as a failure rather
+// than stopping the test.
+func TestFindPanics(t *testing.T) {
+	failure := find(Config{Runs: 10, MaxSize: 5, Seed: 1}, genInts, nil, func(s []int) error {
+		panic("oops")
+	})
+	if failure == nil || failure.Err.Error() != "panicked: oops" {
+		t.Errorf("Expected the panic to be reported, got %v", failure)
+	}
+}
+
+// TestEqual ensures matching implementations pass.
+func TestEqual(t *testing.T) {
+	slow := func(n int) (int, error) {
+		sum := 0
+		for i := 1; i <= n; i++ {
+			sum += i
+		}
+		return sum, nil
+	}
+	fast := func(n int) (int, error) { return n * (n + 1) / 2, nil }
+	gen := func(r *rand.Rand, size int) int { return r.Intn(size * 100) }
+	Equal(t, Config{}, gen, Int, slow, fast)
+}
+
+// TestSeedEnv ensures the seed can be fixed from the environment.
+func TestSeedEnv(t *testing.T) {
+	t.Setenv(SeedEnv, "7")
+	if cfg := (Config{}).mustDefaults(t); cfg.Seed != 7 || cfg.Runs != 200 || cfg.MaxSize != 20 {
+		t.Errorf("Expected seed 7 with default runs and size, got %+v", cfg)
+	}
+	t.Setenv(SeedEnv, "seven")
+	if _, err := (Config{}).withDefaults(); err == nil {
+		t.Error("Expected an error for a seed which isn't a number")
+	}
+}
+
+// TestShrinkers ensures the shrinkers only suggest smaller inputs, never
+// going below the limits given.
+func TestShrinkers(t *testing.T) {
+	cases := []struct {
+		got, want any
+	}{
+		{Int(10), []int{0, 5, 9}},
+		{Int(-3), []int{0, -1, -2}},
+		{Int(1), []int{0}},
+		{Int(0), []int(nil)},
+		{IntAbove(1)(4), []int{1, 2, 3}},
+		{IntAbove(1)(1), []int(nil)},
+		{Slice([]int{1, 2, 3, 4}, 3, nil), [][]int{{2, 3, 4}, {1, 3, 4}, {1, 2, 4}, {1, 2, 3}}},
+		{Slice([]int{1, 2}, 0, Int), [][]int{{2}, {1}, {0, 2}, {1, 0}, {1, 1}}},
+	}
+	for _, c := range cases {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("Expected %v, got %v", c.want, c.got)
+		}
+	}
+}
+
+// mustDefaults fills in the config's defaults, failing the test on error.
+func (cfg Config) mustDefaults(t *testing.T) Config {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
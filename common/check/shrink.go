@@ -0,0 +1,68 @@
+package check
+
+// Int shrinks a number towards zero, trying zero itself first, then half way,
+// then one step closer.
+func Int(n int) []int {
+	var smaller []int
+	for _, m := range []int{0, n / 2, n - sign(n)} {
+		if m != n && (len(smaller) == 0 || smaller[len(smaller)-1] != m) {
+			smaller = append(smaller, m)
+		}
+	}
+	return smaller
+}
+
+// IntAbove shrinks a number towards least, for numbers with a lower limit
+// such as lengths which must be at least one.
+func IntAbove(least int) Shrink[int] {
+	return func(n int) []int {
+		var smaller []int
+		for _, m := range Int(n - least) {
+			smaller = append(smaller, m+least)
+		}
+		return smaller
+	}
+}
+
+// sign returns -1, 0 or 1 to match the sign of n.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Slice shrinks a slice by dropping elements, first half of them at a time,
+// then one at a time, and never below least elements. After that each element
+// is shrunk in place with elem, if it isn't nil. The slice passed in is never
+// changed.
+func Slice[E any](s []E, least int, elem Shrink[E]) [][]E {
+	var smaller [][]E
+
+	// Drop chunks, halving their size down to single elements
+	for chunk := len(s) / 2; chunk >= 1; chunk /= 2 {
+		if len(s)-chunk < least {
+			continue
+		}
+		for start := 0; start+chunk <= len(s); start += chunk {
+			shorter := make([]E, 0, len(s)-chunk)
+			shorter = append(shorter, s[:start]...)
+			smaller = append(smaller, append(shorter, s[start+chunk:]...))
+		}
+	}
+
+	if elem == nil {
+		return smaller
+	}
+	for i, e := range s {
+		for _, replacement := range elem(e) {
+			changed := append([]E(nil), s...)
+			changed[i] = replacement
+			smaller = append(smaller, changed)
+		}
+	}
+	return smaller
+}
@@ -0,0 +1,374 @@
+// Package common provides utility functions shared across the project.
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v2"
+)
+
+// RepoConfigFileName is the config file in the repository root, shared by
+// every day.
+const RepoConfigFileName = "aoc.yaml"
+
+// DayConfigFileName is the config file in a day's directory.
+const DayConfigFileName = "config.yaml"
+
+// Config is the effective configuration for a day. Use LoadConfig to build it
+// from its layers.
+type Config struct {
+	InputFile string `yaml:"inputFile"`
+	LogLevel  string `yaml:"logLevel"`
+	Session   string `yaml:"session"` // Advent of Code session cookie
+	BaseURL   string `yaml:"baseURL"` // Advent of Code website, for testing
+
+	LogFormat     string `yaml:"logFormat"`     // console, json or logfmt
+	LogFile       string `yaml:"logFile"`       // Also log to this file when set
+	LogMaxSize    int    `yaml:"logMaxSize"`    // Megabytes before the log file is rotated
+	LogMaxBackups int    `yaml:"logMaxBackups"` // Rotated log files to keep
+
+	// Verify asks solutions to check their answers against a slower, simpler
+	// version where they have one, failing if the two disagree.
+	Verify bool `yaml:"verify"`
+
+	// Render asks solutions which can draw their puzzle to do so, through the
+	// RunContext's Frames, played at FPS frames per second.
+	Render bool `yaml:"render"`
+	FPS    int  `yaml:"fps"`
+
+	// Inputs are named sets of input files, e.g. "test", so each part can be
+	// run against the example meant for it without editing InputFile.
+	Inputs map[string]InputSet `yaml:"inputs"`
+
+	// Sources records where each key's value came from, e.g. "default" or
+	// "2023/day_07/config.yaml". Input sets are keyed as "inputs.NAME".
+	Sources map[string]string `yaml:"-"`
+}
+
+// Override sets a config key from somewhere other than a file, such as a
+// command line flag.
+type Override struct {
+	Key    string // Key as written in the config files, e.g. logLevel
+	Value  string
+	Source string // e.g. "flag --log-level"
+}
+
+// configKey is a single valued key and the environment variable which sets it.
+type configKey struct {
+	name string
+	env  string
+	get  func(cfg Config) string
+	set  func(cfg *Config, value string) error
+}
+
+// stringKey is a key held in a string field.
+func stringKey(name, env string, field func(cfg *Config) *string) configKey {
+	return configKey{
+		name: name,
+		env:  env,
+		get:  func(cfg Config) string { return *field(&cfg) },
+		set: func(cfg *Config, value string) error {
+			*field(cfg) = value
+			return nil
+		},
+	}
+}
+
+// intKey is a key held in an int field.
+func intKey(name, env string, field func(cfg *Config) *int) configKey {
+	return configKey{
+		name: name,
+		env:  env,
+		get:  func(cfg Config) string { return strconv.Itoa(*field(&cfg)) },
+		set: func(cfg *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a whole number, got %q", name, value)
+			}
+			*field(cfg) = n
+			return nil
+		},
+	}
+}
+
+// boolKey is a key held in a bool field.
+func boolKey(name, env string, field func(cfg *Config) *bool) configKey {
+	return configKey{
+		name: name,
+		env:  env,
+		get:  func(cfg Config) string { return strconv.FormatBool(*field(&cfg)) },
+		set: func(cfg *Config, value string) error {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s must be true or false, got %q", name, value)
+			}
+			*field(cfg) = b
+			return nil
+		},
+	}
+}
+
+// configKeys are the keys which can be set by every layer. Input sets can only
+// be set in files.
+var configKeys = []configKey{
+	stringKey("inputFile", "AOC_INPUT_FILE", func(cfg *Config) *string { return &cfg.InputFile }),
+	stringKey("logLevel", "AOC_LOG_LEVEL", func(cfg *Config) *string { return &cfg.LogLevel }),
+	stringKey("session", "AOC_SESSION", func(cfg *Config) *string { return &cfg.Session }),
+	stringKey("baseURL", "AOC_BASE_URL", func(cfg *Config) *string { return &cfg.BaseURL }),
+	stringKey("logFormat", "AOC_LOG_FORMAT", func(cfg *Config) *string { return &cfg.LogFormat }),
+	stringKey("logFile", "AOC_LOG_FILE", func(cfg *Config) *string { return &cfg.LogFile }),
+	intKey("logMaxSize", "AOC_LOG_MAX_SIZE", func(cfg *Config) *int { return &cfg.LogMaxSize }),
+	intKey("logMaxBackups", "AOC_LOG_MAX_BACKUPS", func(cfg *Config) *int { return &cfg.LogMaxBackups }),
+	boolKey("verify", "AOC_VERIFY", func(cfg *Config) *bool { return &cfg.Verify }),
+	boolKey("render", "AOC_RENDER", func(cfg *Config) *bool { return &cfg.Render }),
+	intKey("fps", "AOC_FPS", func(cfg *Config) *int { return &cfg.FPS }),
+}
+
+// DefaultConfig returns the built in defaults, the bottom layer of every
+// config.
+func DefaultConfig() Config {
+	cfg := Config{
+		InputFile:     "input.txt",
+		LogLevel:      "Info",
+		LogFormat:     "console",
+		LogMaxSize:    10,
+		LogMaxBackups: 3,
+		FPS:           10,
+		Inputs:        make(map[string]InputSet),
+		Sources:       make(map[string]string),
+	}
+	for _, key := range configKeys {
+		cfg.Sources[key.name] = "default"
+	}
+	return cfg
+}
+
+// LoadConfig builds a day's config from its layers, each overriding the ones
+// before it:
+//
+//  1. built in defaults
+//  2. aoc.yaml in the repository root
+//  3. config.yaml in the day's directory
+//  4. AOC_* environment variables
+//  5. overrides, usually from command line flags
+//
+// Either file may be missing. Unknown keys in a file or override are an error.
+// Pass an empty dayDir for commands which aren't about a single day.
+func LoadConfig(root, dayDir string, overrides ...Override) (Config, error) {
+	cfg := DefaultConfig()
+
+	paths := []string{filepath.Join(root, RepoConfigFileName)}
+	if dayDir != "" {
+		paths = append(paths, filepath.Join(dayDir, DayConfigFileName))
+	}
+	for _, path := range paths {
+		if err := cfg.applyFile(path); err != nil {
+			return cfg, err
+		}
+	}
+
+	for _, key := range configKeys {
+		if value, ok := os.LookupEnv(key.env); ok && value != "" {
+			if err := key.set(&cfg, value); err != nil {
+				return cfg, fmt.Errorf("env %s: %w", key.env, err)
+			}
+			cfg.Sources[key.name] = "env " + key.env
+		}
+	}
+
+	for _, o := range overrides {
+		key, ok := lookupConfigKey(o.Key)
+		if !ok {
+			return cfg, fmt.Errorf("%s: unknown config key %q", o.Source, o.Key)
+		}
+		if err := key.set(&cfg, o.Value); err != nil {
+			return cfg, fmt.Errorf("%s: %w", o.Source, err)
+		}
+		cfg.Sources[key.name] = o.Source
+	}
+
+	return cfg, cfg.validate()
+}
+
+// applyFile layers a config file on top of cfg. A missing file is skipped.
+func (cfg *Config) applyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Decode keys in order, so unknown ones can be named
+	var file yaml.MapSlice
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	source := filepath.ToSlash(path)
+	for _, item := range file {
+		name := fmt.Sprint(item.Key)
+		if name == "inputs" {
+			if err := cfg.applyInputs(item.Value, source); err != nil {
+				return fmt.Errorf("parsing %s: inputs: %w", path, err)
+			}
+			continue
+		}
+
+		key, ok := lookupConfigKey(name)
+		if !ok {
+			return fmt.Errorf("parsing %s: unknown key %q", path, name)
+		}
+		switch item.Value.(type) {
+		case string, int, bool, float64:
+		default:
+			return fmt.Errorf("parsing %s: %s must be a single value", path, name)
+		}
+		if err := key.set(cfg, fmt.Sprint(item.Value)); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		cfg.Sources[name] = source
+	}
+	return nil
+}
+
+// applyInputs layers a file's input sets on top of cfg. Sets are replaced
+// whole, so a day's "test" set replaces the repository's.
+func (cfg *Config) applyInputs(value interface{}, source string) error {
+	// Round trip the value so sets are decoded strictly, like the file
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var inputs map[string]InputSet
+	if err := yaml.UnmarshalStrict(data, &inputs); err != nil {
+		return err
+	}
+
+	for name, set := range inputs {
+		cfg.Inputs[name] = set
+		cfg.Sources["inputs."+name] = source
+	}
+	return nil
+}
+
+// lookupConfigKey finds a single valued key by name.
+func lookupConfigKey(name string) (configKey, bool) {
+	for _, key := range configKeys {
+		if key.name == name {
+			return key, true
+		}
+	}
+	return configKey{}, false
+}
+
+// validate checks the effective values make sense, naming where a bad value
+// came from.
+func (cfg Config) validate() error {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return fmt.Errorf("logLevel from %s: %w", cfg.Sources["logLevel"], err)
+	}
+	if cfg.InputFile == "" {
+		return fmt.Errorf("inputFile from %s is empty", cfg.Sources["inputFile"])
+	}
+	switch cfg.LogFormat {
+	case "console", "json", "logfmt":
+	default:
+		return fmt.Errorf("logFormat from %s must be console, json or logfmt, got %q", cfg.Sources["logFormat"], cfg.LogFormat)
+	}
+	if cfg.LogMaxSize < 1 {
+		return fmt.Errorf("logMaxSize from %s must be at least 1", cfg.Sources["logMaxSize"])
+	}
+	if cfg.LogMaxBackups < 0 {
+		return fmt.Errorf("logMaxBackups from %s can't be negative", cfg.Sources["logMaxBackups"])
+	}
+	if cfg.FPS < 1 {
+		return fmt.Errorf("fps from %s must be at least 1", cfg.Sources["fps"])
+	}
+	return nil
+}
+
+// Describe returns each effective value and where it came from, one per line,
+// for debugging. The session cookie is masked.
+func (cfg Config) Describe() string {
+	var buf bytes.Buffer
+	for _, key := range configKeys {
+		value := key.get(cfg)
+		if key.name == "session" && value != "" {
+			value = "(set)"
+		}
+		fmt.Fprintf(&buf, "%s: %q (%s)\n", key.name, value, cfg.Sources[key.name])
+	}
+
+	names := make([]string, 0, len(cfg.Inputs))
+	for name := range cfg.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		set := cfg.Inputs[name]
+		fmt.Fprintf(&buf, "inputs.%s: part1 %q, part2 %q (%s)\n", name, set.Part1, set.Part2, cfg.Sources["inputs."+name])
+	}
+	return buf.String()
+}
+
+// InputSet is the input file each part runs against. A part with no file
+// isn't run when the set is selected.
+type InputSet struct {
+	Part1 string `yaml:"part1"`
+	Part2 string `yaml:"part2"`
+}
+
+// UnmarshalYAML allows a set to be a single file name used for both parts, as
+// well as a file per part.
+func (s *InputSet) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var file string
+	if err := unmarshal(&file); err == nil {
+		*s = InputSet{Part1: file, Part2: file}
+		return nil
+	}
+
+	type plain InputSet // Avoids calling this method again
+	return unmarshal((*plain)(s))
+}
+
+// File returns the input file for a part, or an empty string if the part has
+// none.
+func (s InputSet) File(part int) string {
+	if part == 1 {
+		return s.Part1
+	}
+	return s.Part2
+}
+
+// SelectInputs returns the named input set. An empty name gives both parts the
+// config's InputFile.
+func (cfg Config) SelectInputs(name string) (InputSet, error) {
+	if name == "" {
+		return InputSet{Part1: cfg.InputFile, Part2: cfg.InputFile}, nil
+	}
+
+	set, ok := cfg.Inputs[name]
+	if !ok {
+		var names []string
+		for n := range cfg.Inputs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return set, fmt.Errorf("no input set %q, the config has no inputs", name)
+		}
+		return set, fmt.Errorf("no input set %q, expected one of: %s", name, strings.Join(names, ", "))
+	}
+	return set, nil
+}
@@ -0,0 +1,132 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes a test file, creating its directory.
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestLoadConfig ensures each layer overrides the ones before it and the
+// source of each value is recorded.
+func TestLoadConfig(t *testing.T) {
+	root := t.TempDir()
+	dir := DayDir(root, 2023, 1)
+	writeFile(t, filepath.Join(root, RepoConfigFileName), "logLevel: Warn\nbaseURL: http://repo\ninputs:\n  test: test_input.txt\n  real: input.txt\n")
+	writeFile(t, filepath.Join(dir, DayConfigFileName), "logLevel: Debug\ninputs:\n  test:\n    part1: test_input_01.txt\n    part2: test_input_02.txt\n")
+	t.Setenv("AOC_BASE_URL", "http://env")
+	t.Setenv("AOC_SESSION", "")
+
+	cfg, err := LoadConfig(root, dir, Override{Key: "inputFile", Value: "other.txt", Source: "flag --input"})
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+
+	dayFile := filepath.ToSlash(filepath.Join(dir, DayConfigFileName))
+	repoFile := filepath.ToSlash(filepath.Join(root, RepoConfigFileName))
+	cases := []struct {
+		key, value, source string
+	}{
+		{"inputFile", cfg.InputFile, "flag --input"},
+		{"logLevel", cfg.LogLevel, dayFile},
+		{"baseURL", cfg.BaseURL, "env AOC_BASE_URL"},
+		{"session", cfg.Session, "default"},
+		{"inputs.test", cfg.Inputs["test"].Part2, dayFile},
+		{"inputs.real", cfg.Inputs["real"].Part1, repoFile},
+	}
+	want := map[string]string{
+		"inputFile":   "other.txt",
+		"logLevel":    "Debug",
+		"baseURL":     "http://env",
+		"session":     "",
+		"inputs.test": "test_input_02.txt",
+		"inputs.real": "input.txt",
+	}
+	for _, c := range cases {
+		if c.value != want[c.key] || cfg.Sources[c.key] != c.source {
+			t.Errorf("Expected %s to be %q from %s, got %q from %s", c.key, want[c.key], c.source, c.value, cfg.Sources[c.key])
+		}
+	}
+
+	if described := cfg.Describe(); !strings.Contains(described, `logLevel: "Debug" (`+dayFile+`)`) {
+		t.Errorf("Expected Describe to show the log level's source, got:\n%s", described)
+	}
+}
+
+// TestLoadConfigDefaults ensures a day with no config files gets the defaults.
+func TestLoadConfigDefaults(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AOC_LOG_LEVEL", "")
+	t.Setenv("AOC_INPUT_FILE", "")
+
+	cfg, err := LoadConfig(root, DayDir(root, 2023, 1))
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.InputFile != "input.txt" || cfg.LogLevel != "Info" || cfg.Sources["logLevel"] != "default" {
+		t.Errorf("Expected the defaults, got %+v", cfg)
+	}
+}
+
+// TestLoadConfigInvalid ensures unknown keys and bad values are rejected.
+func TestLoadConfigInvalid(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    string
+		overrides []Override
+	}{
+		{"unknown key", "inputfile: input.txt\n", nil},
+		{"unknown set key", "inputs:\n  test:\n    part3: test_input.txt\n", nil},
+		{"bad log level", "logLevel: Loud\n", nil},
+		{"unknown override", "", []Override{{Key: "colour", Value: "red", Source: "flag --colour"}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFile(t, filepath.Join(root, RepoConfigFileName), c.config)
+			if _, err := LoadConfig(root, "", c.overrides...); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+// TestSelectInputs ensures input sets can be a single file or a file per part.
+func TestSelectInputs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Inputs = map[string]InputSet{
+		"test":    {Part1: "test_input_01.txt", Part2: "test_input_02.txt"},
+		"example": {Part1: "test_input_01.txt"},
+	}
+
+	cases := []struct {
+		name string
+		want InputSet
+	}{
+		{"", InputSet{Part1: "input.txt", Part2: "input.txt"}},
+		{"test", InputSet{Part1: "test_input_01.txt", Part2: "test_input_02.txt"}},
+		{"example", InputSet{Part1: "test_input_01.txt"}},
+	}
+	for _, c := range cases {
+		got, err := cfg.SelectInputs(c.name)
+		if err != nil || got != c.want {
+			t.Errorf("Expected set %q to be %+v, got %+v (err %v)", c.name, c.want, got, err)
+		}
+	}
+
+	if _, err := cfg.SelectInputs("missing"); err == nil {
+		t.Errorf("Expected an error for a missing set")
+	}
+}
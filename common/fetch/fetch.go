@@ -0,0 +1,213 @@
+// Package fetch downloads puzzle inputs from the Advent of Code website and
+// caches them in each day's directory.
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the Advent of Code website.
+	DefaultBaseURL = "https://adventofcode.com"
+	// DefaultUserAgent identifies this repo to the Advent of Code maintainers
+	// as they ask for automated tools to do.
+	DefaultUserAgent = "github.com/jonoricci/advent-of-code-go"
+	// DefaultMinInterval is the shortest time allowed between two requests.
+	DefaultMinInterval = 5 * time.Second
+	// InputFileName is the file a day's real input is cached in.
+	InputFileName = "input.txt"
+	// LastRequestFileName is the file, kept in the repository root, which
+	// records when the last request was made.
+	LastRequestFileName = ".aoc_last_request"
+	// SessionEnvVar is the environment variable which sets the session token.
+	SessionEnvVar = "AOC_SESSION"
+)
+
+// ErrNoSession is returned when a request needs a session token but none has
+// been configured.
+var ErrNoSession = errors.New("no session token, set " + SessionEnvVar + " or session in config")
+
+// Client makes polite requests to the Advent of Code website.
+type Client struct {
+	BaseURL     string
+	Session     string
+	UserAgent   string
+	MinInterval time.Duration
+	HTTPClient  *http.Client
+	// LastRequestFile records when the last request was made, so separate
+	// runs of aoc keep to MinInterval too. Empty limits only this client.
+	LastRequestFile string
+
+	mu          sync.Mutex
+	lastRequest time.Time
+	now         func() time.Time
+	sleep       func(time.Duration)
+}
+
+// NewClient returns a client for the given base URL and session token. An
+// empty base URL uses DefaultBaseURL.
+func NewClient(baseURL, session string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:     strings.TrimRight(baseURL, "/"),
+		Session:     strings.TrimSpace(session),
+		UserAgent:   DefaultUserAgent,
+		MinInterval: DefaultMinInterval,
+		HTTPClient:  &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+		sleep:       time.Sleep,
+	}
+}
+
+// Unlocked reports whether a puzzle has been released. Puzzles unlock at
+// midnight US Eastern time (UTC-5) on their day in December.
+func Unlocked(year, day int, now time.Time) bool {
+	release := time.Date(year, time.December, day, 5, 0, 0, 0, time.UTC)
+	return !now.Before(release)
+}
+
+// Input downloads the real puzzle input for a year and day.
+func (c *Client) Input(year, day int) ([]byte, error) {
+	if !Unlocked(year, day, c.now()) {
+		return nil, fmt.Errorf("%d day %02d has not unlocked yet", year, day)
+	}
+	return c.Get(fmt.Sprintf("/%d/day/%d/input", year, day))
+}
+
+// Get performs a rate limited, authenticated GET request for a path below the
+// base URL and returns the response body.
+func (c *Client) Get(path string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends a request with the session cookie and User-Agent set, waiting first
+// if the previous request was too recent. A non 200 response is an error.
+func (c *Client) Do(req *http.Request) ([]byte, error) {
+	if c.Session == "" {
+		return nil, ErrNoSession
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError:
+		// The site answers an expired or invalid session with one of these
+		return nil, fmt.Errorf("%s %s: %s, check the session token", req.Method, req.URL.Path, resp.Status)
+	default:
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// wait blocks until MinInterval has passed since the previous request, made
+// by this client or, with LastRequestFile set, by any other.
+func (c *Client) wait() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := c.lastRequest
+	if c.LastRequestFile != "" {
+		saved, err := readLastRequest(c.LastRequestFile)
+		if err != nil {
+			return err
+		}
+		if saved.After(last) {
+			last = saved
+		}
+	}
+
+	if !last.IsZero() {
+		// A time in the future, from a clock being changed, still only waits
+		// for one interval
+		if remaining := min(c.MinInterval-c.now().Sub(last), c.MinInterval); remaining > 0 {
+			c.sleep(remaining)
+		}
+	}
+	c.lastRequest = c.now()
+
+	if c.LastRequestFile == "" {
+		return nil
+	}
+	stamp := c.lastRequest.Format(time.RFC3339Nano) + "\n"
+	if err := os.WriteFile(c.LastRequestFile, []byte(stamp), 0o644); err != nil {
+		return fmt.Errorf("recording request time: %w", err)
+	}
+	return nil
+}
+
+// readLastRequest reads the time saved in a LastRequestFile. A missing file
+// means no request has been made yet.
+func readLastRequest(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading last request time: %w", err)
+	}
+	last, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s doesn't hold a time, delete it to carry on: %w", path, err)
+	}
+	return last, nil
+}
+
+// Download saves the real input for a year and day to InputFileName in dir.
+// An input already on disk is never downloaded again, the returned bool
+// reports whether the cached copy was used.
+func (c *Client) Download(year, day int, dir string) (string, bool, error) {
+	path := filepath.Join(dir, InputFileName)
+
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return path, true, nil
+	}
+
+	data, err := c.Input(year, day)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", false, err
+	}
+
+	// Write to a temporary file first so an interrupted write never looks
+	// like a cached input
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", false, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", false, err
+	}
+	return path, false, nil
+}
@@ -0,0 +1,174 @@
+package fetch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestServer returns a stand-in for the Advent of Code website which serves
+// an input for any day and counts the requests it receives.
+func newTestServer(t *testing.T, hits *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		if r.UserAgent() != DefaultUserAgent {
+			t.Errorf("Expected User-Agent %q, got %q", DefaultUserAgent, r.UserAgent())
+		}
+		if r.URL.Path != "/2023/day/7/input" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("32T3K 765\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestDownload ensures an input is downloaded once and then read from disk.
+func TestDownload(t *testing.T) {
+	hits := 0
+	server := newTestServer(t, &hits)
+	client := NewClient(server.URL, "secret")
+	client.MinInterval = 0
+	dir := filepath.Join(t.TempDir(), "day_07")
+
+	path, cached, err := client.Download(2023, 7, dir)
+	if err != nil {
+		t.Fatalf("Download returned an error: %v", err)
+	}
+	if cached {
+		t.Errorf("Expected first download not to be cached")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading downloaded input: %v", err)
+	}
+	if string(data) != "32T3K 765\n" {
+		t.Errorf("Expected input %q, got %q", "32T3K 765\n", data)
+	}
+
+	_, cached, err = client.Download(2023, 7, dir)
+	if err != nil {
+		t.Fatalf("Download returned an error: %v", err)
+	}
+	if !cached || hits != 1 {
+		t.Errorf("Expected second download to use the cache, cached %v with %d requests", cached, hits)
+	}
+}
+
+// TestDownloadErrors ensures failed requests return an error and don't leave
+// a cached input behind.
+func TestDownloadErrors(t *testing.T) {
+	hits := 0
+	server := newTestServer(t, &hits)
+
+	cases := []struct {
+		name    string
+		session string
+		year    int
+		day     int
+	}{
+		{"no session", "", 2023, 7},
+		{"bad session", "wrong", 2023, 7},
+		{"missing day", "secret", 2023, 8},
+		{"locked day", "secret", 2999, 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := NewClient(server.URL, c.session)
+			client.MinInterval = 0
+			dir := t.TempDir()
+
+			if _, _, err := client.Download(c.year, c.day, dir); err == nil {
+				t.Errorf("Expected Download to return an error")
+			}
+			if _, err := os.Stat(filepath.Join(dir, InputFileName)); !os.IsNotExist(err) {
+				t.Errorf("Expected no input file to be written")
+			}
+		})
+	}
+}
+
+// TestRateLimit ensures requests closer together than MinInterval wait.
+func TestRateLimit(t *testing.T) {
+	hits := 0
+	server := newTestServer(t, &hits)
+	client := NewClient(server.URL, "secret")
+
+	now := time.Date(2023, time.December, 25, 12, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	client.now = func() time.Time { return now }
+	client.sleep = func(d time.Duration) {
+		slept += d
+		now = now.Add(d)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Input(2023, 7); err != nil {
+			t.Fatalf("Input returned an error: %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	// The first request is immediate and the next two wait out the interval
+	want := 2 * (DefaultMinInterval - time.Second)
+	if slept != want {
+		t.Errorf("Expected to sleep for %v, slept for %v", want, slept)
+	}
+}
+
+// TestRateLimitShared ensures a new client waits out a request made by an
+// earlier one through LastRequestFile, as a separate run of aoc would.
+func TestRateLimitShared(t *testing.T) {
+	hits := 0
+	server := newTestServer(t, &hits)
+	file := filepath.Join(t.TempDir(), LastRequestFileName)
+
+	now := time.Date(2023, time.December, 25, 12, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	newClient := func() *Client {
+		client := NewClient(server.URL, "secret")
+		client.LastRequestFile = file
+		client.now = func() time.Time { return now }
+		client.sleep = func(d time.Duration) {
+			slept += d
+			now = now.Add(d)
+		}
+		return client
+	}
+
+	if _, err := newClient().Input(2023, 7); err != nil {
+		t.Fatalf("Input returned an error: %v", err)
+	}
+	if slept != 0 {
+		t.Errorf("Expected the first request not to wait, slept for %v", slept)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := newClient().Input(2023, 7); err != nil {
+		t.Fatalf("Input returned an error: %v", err)
+	}
+	if want := DefaultMinInterval - time.Second; slept != want {
+		t.Errorf("Expected to sleep for %v, slept for %v", want, slept)
+	}
+
+	if err := os.WriteFile(file, []byte("yesterday\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newClient().Input(2023, 7); err == nil {
+		t.Error("Expected an error for a file which doesn't hold a time")
+	}
+	if hits != 2 {
+		t.Errorf("Expected 2 requests to reach the server, got %d", hits)
+	}
+}
@@ -0,0 +1,96 @@
+// Package common provides utility functions shared across the project.
+package common
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Generator makes a random, valid puzzle input as the lines of an input file.
+// Size says roughly how big the input should be, such as how many lines or
+// how long a loop, and is up to each day to interpret. The same random source
+// and size always give the same input.
+type Generator func(r *rand.Rand, size int) []string
+
+// Known is a generated input along with the answers its generator worked out
+// while making it. A part whose answer isn't known is the zero Answer, which
+// is empty.
+type Known struct {
+	Lines        []string
+	Part1, Part2 Answer
+}
+
+// KnownGenerator makes an input like a Generator, along with any answers it
+// knows from how the input was made, such as half the length of a loop it
+// drew. These check the solution against far more inputs than the examples.
+type KnownGenerator func(r *rand.Rand, size int) Known
+
+// generators holds every registered Generator keyed by year and then day.
+var generators = make(map[int]map[int]Generator)
+
+// knownGenerators holds the generators which also know their answers, keyed
+// by year and then day.
+var knownGenerators = make(map[int]map[int]KnownGenerator)
+
+// RegisterGenerator adds a day's input generator, so inputs far larger than
+// the real one can be made to test how the solution scales. Like Register it
+// is intended to be called from a day's init function and panics if the same
+// year and day is registered twice.
+func RegisterGenerator(year, day int, gen Generator) {
+	if generators[year] == nil {
+		generators[year] = make(map[int]Generator)
+	}
+	if _, exists := generators[year][day]; exists {
+		panic(fmt.Sprintf("generator for %d day %02d already registered", year, day))
+	}
+	generators[year][day] = gen
+}
+
+// RegisterKnownGenerator adds a day's input generator which also knows the
+// answers to its inputs. It is registered as the day's Generator too, so
+// everything else uses it the same way.
+func RegisterKnownGenerator(year, day int, gen KnownGenerator) {
+	RegisterGenerator(year, day, func(r *rand.Rand, size int) []string {
+		return gen(r, size).Lines
+	})
+	if knownGenerators[year] == nil {
+		knownGenerators[year] = make(map[int]KnownGenerator)
+	}
+	knownGenerators[year][day] = gen
+}
+
+// LookupGenerator returns the registered generator for a year and day.
+func LookupGenerator(year, day int) (Generator, error) {
+	gen, exists := generators[year][day]
+	if !exists {
+		return nil, fmt.Errorf("no generator registered for %d day %02d", year, day)
+	}
+	return gen, nil
+}
+
+// Generate makes an input for a day from a seed, which can be given again to
+// make the same input.
+func Generate(year, day, size int, seed int64) ([]string, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("size must be at least 1, got %d", size)
+	}
+	gen, err := LookupGenerator(year, day)
+	if err != nil {
+		return nil, err
+	}
+	return gen(rand.New(rand.NewSource(seed)), size), nil
+}
+
+// GenerateKnown makes the same input as Generate, along with any answers the
+// day's generator knows. A day registered with RegisterGenerator knows none.
+func GenerateKnown(year, day, size int, seed int64) (Known, error) {
+	gen, exists := knownGenerators[year][day]
+	if !exists {
+		lines, err := Generate(year, day, size, seed)
+		return Known{Lines: lines}, err
+	}
+	if size < 1 {
+		return Known{}, fmt.Errorf("size must be at least 1, got %d", size)
+	}
+	return gen(rand.New(rand.NewSource(seed)), size), nil
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// TestGenerate ensures the same seed makes the same input and days without a
+// generator are reported.
+func TestGenerate(t *testing.T) {
+	RegisterGenerator(1999, 1, func(r *rand.Rand, size int) []string {
+		lines := make([]string, size)
+		for i := range lines {
+			lines[i] = strconv.Itoa(r.Intn(1000))
+		}
+		return lines
+	})
+
+	first, err := Generate(1999, 1, 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Generate(1999, 1, 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 5 || !reflect.DeepEqual(first, again) {
+		t.Errorf("Expected the same 5 lines from the same seed, got %v and %v", first, again)
+	}
+
+	if _, err := Generate(1999, 1, 0, 42); err == nil {
+		t.Error("Expected an error for a size of 0")
+	}
+	if _, err := Generate(1999, 2, 5, 42); err == nil {
+		t.Error("Expected an error for a day without a generator")
+	}
+}
+
+// TestGenerateKnown ensures a known generator's answers come with the same
+// input Generate makes, and other days know no answers.
+func TestGenerateKnown(t *testing.T) {
+	RegisterKnownGenerator(1999, 3, func(r *rand.Rand, size int) Known {
+		lines := make([]string, size)
+		sum := 0
+		for i := range lines {
+			n := r.Intn(1000)
+			lines[i] = strconv.Itoa(n)
+			sum += n
+		}
+		return Known{Lines: lines, Part1: Int(sum)}
+	})
+
+	known, err := GenerateKnown(1999, 3, 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := Generate(1999, 3, 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(known.Lines, lines) {
+		t.Errorf("Expected the same lines as Generate, got %v and %v", known.Lines, lines)
+	}
+	if known.Part1.String() == "" || known.Part2.String() != "" {
+		t.Errorf("Expected only a Part 1 answer, got %q and %q", known.Part1, known.Part2)
+	}
+
+	RegisterGenerator(1999, 4, func(r *rand.Rand, size int) []string { return []string{"1"} })
+	if known, err := GenerateKnown(1999, 4, 5, 42); err != nil || len(known.Lines) != 1 || known.Part1.String() != "" {
+		t.Errorf("Expected one line with no answers, got %+v, %v", known, err)
+	}
+	if _, err := GenerateKnown(1999, 3, 0, 42); err == nil {
+		t.Error("Expected an error for a size of 0")
+	}
+}
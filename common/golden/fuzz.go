@@ -0,0 +1,75 @@
+package golden
+
+import (
+	"context"
+	"jonoricci/advent-of-code-go/common"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fuzzTimeout is how long parsing and both parts get to run on one fuzzed
+// input. A part which checks its RunContext gives up after this, which is
+// fine, as only panics fail the test.
+const fuzzTimeout = time.Second
+
+// FuzzParts fuzzes a day's solution, parsing the input then running both
+// parts, and fails if any of them panics. Errors are fine, as malformed input
+// should be reported rather than crash the program. It is seeded with the
+// day's example inputs, or input.txt if there are none, as well as anything
+// kept under testdata/fuzz.
+func FuzzParts(f *testing.F, dir string, year, day int) {
+	f.Helper()
+	puzzle, err := common.Lookup(year, day)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	inputs, err := InputFiles(dir)
+	if err != nil {
+		f.Fatal(err)
+	}
+	var examples []string
+	for _, input := range inputs {
+		if strings.HasPrefix(input, "test_input") {
+			examples = append(examples, input)
+		}
+	}
+	if len(examples) == 0 {
+		examples = inputs
+	}
+	for _, input := range examples {
+		data, err := os.ReadFile(filepath.Join(dir, input))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(data))
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		ctx, cancel := context.WithTimeout(context.Background(), fuzzTimeout)
+		defer cancel()
+		rc := common.NewRunContext(ctx, nil, common.DefaultConfig(), year, day).WithInput("fuzz")
+
+		// Report the input with the panic, as the fuzzer's own report of a
+		// panic doesn't say which step it came from
+		step := "Parse"
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("%s panicked on input %q: %v\n%s", step, text, r, debug.Stack())
+			}
+		}()
+
+		parsed, err := puzzle.Parse(rc, common.SplitLines(text))
+		if err != nil {
+			return
+		}
+		step = "Part1"
+		puzzle.Part1(rc.WithPart(1), parsed)
+		step = "Part2"
+		puzzle.Part2(rc.WithPart(2), parsed)
+	})
+}
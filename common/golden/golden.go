@@ -0,0 +1,213 @@
+// Package golden checks solutions against the expected answers recorded in
+// each day's answers.yaml, so a regression reports exactly which input and
+// part changed.
+package golden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/parse"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"gopkg.in/yaml.v2"
+)
+
+// FileName is the file each day's expected answers are kept in.
+const FileName = "answers.yaml"
+
+// Parts is the expected answer to each part for one input file. An empty
+// answer means the part isn't checked against that input, for example when
+// an example input is only given for one of the parts. Unverified marks
+// answers recorded from the solution's own output rather than checked by
+// hand or on the website, which catch a change but not a wrong solution.
+type Parts struct {
+	Part1      string `yaml:"part1,omitempty"`
+	Part2      string `yaml:"part2,omitempty"`
+	Unverified bool   `yaml:"unverified,omitempty"`
+}
+
+// Answers maps an input file name to its expected answers.
+type Answers map[string]Parts
+
+// Load reads the expected answers from a day's directory. A day without an
+// answers file has no answers yet.
+func Load(dir string) (Answers, error) {
+	answers := make(Answers)
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return answers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.UnmarshalStrict(data, &answers); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(dir, FileName), err)
+	}
+	return answers, nil
+}
+
+// Inputs returns the input file names with recorded answers in sorted order.
+func (a Answers) Inputs() []string {
+	var inputs []string
+	for input := range a {
+		inputs = append(inputs, input)
+	}
+	sort.Strings(inputs)
+	return inputs
+}
+
+// header starts every answers file written by Save.
+const header = "# Expected answers for each input file, checked by go test.\n"
+
+// Save writes the answers to a day's directory, replacing its answers file.
+func Save(dir string, answers Answers) error {
+	data, err := yaml.Marshal(answers)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, FileName), append([]byte(header), data...), 0o644)
+}
+
+// InputFiles returns the names of a day's input files, every test_input*.txt
+// and input.txt which exist, sorted.
+func InputFiles(dir string) ([]string, error) {
+	inputs, err := filepath.Glob(filepath.Join(dir, "test_input*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "input.txt")); err == nil {
+		inputs = append(inputs, "input.txt")
+	}
+	for i, input := range inputs {
+		inputs[i] = filepath.Base(input)
+	}
+	sort.Strings(inputs)
+	return inputs, nil
+}
+
+// AddLines adds every line of a day's input files to a fuzz test's seed
+// corpus, for fuzzing functions which read one line.
+func AddLines(f *testing.F, dir string) {
+	f.Helper()
+	addSeeds(f, dir, func(lines []string) [][]string {
+		seeds := make([][]string, len(lines))
+		for i, line := range lines {
+			seeds[i] = []string{line}
+		}
+		return seeds
+	})
+}
+
+// AddSections adds every blank line separated section of a day's input files
+// to a fuzz test's seed corpus, one seed per section with its lines joined
+// by newlines, for fuzzing functions which read a group of lines.
+func AddSections(f *testing.F, dir string) {
+	f.Helper()
+	addSeeds(f, dir, func(lines []string) [][]string {
+		var seeds [][]string
+		for _, section := range parse.Sections(parse.Lines("seed", lines)) {
+			seed := make([]string, len(section))
+			for i, line := range section {
+				seed[i] = line.Text
+			}
+			seeds = append(seeds, seed)
+		}
+		return seeds
+	})
+}
+
+// addSeeds reads each input file and adds the pieces split picks out of it to
+// the seed corpus.
+func addSeeds(f *testing.F, dir string, split func(lines []string) [][]string) {
+	f.Helper()
+	inputs, err := InputFiles(dir)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, input := range inputs {
+		data, err := os.ReadFile(filepath.Join(dir, input))
+		if err != nil {
+			f.Fatal(err)
+		}
+		for _, seed := range split(common.SplitLines(string(data))) {
+			f.Add(strings.Join(seed, "\n"))
+		}
+	}
+}
+
+// Check runs a day's solution against every input in its answers file, with
+// a subtest per input and part, e.g. "test_input.txt/part1".
+func Check(t *testing.T, dir string, puzzle common.Puzzle) {
+	t.Helper()
+	CheckConfig(t, dir, puzzle, common.DefaultConfig())
+}
+
+// CheckConfig is Check with the given config, such as one with Verify set.
+func CheckConfig(t *testing.T, dir string, puzzle common.Puzzle, cfg common.Config) {
+	t.Helper()
+
+	answers, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) == 0 {
+		t.Skipf("no answers recorded in %s", filepath.Join(dir, FileName))
+	}
+
+	for _, input := range answers.Inputs() {
+		expected := answers[input]
+		t.Run(input, func(t *testing.T) {
+			if expected.Unverified {
+				t.Logf("answers for %s are unverified, check them then remove unverified from %s", input, FileName)
+			}
+
+			// Log through the test so output only shows for failures
+			logger := zaptest.NewLogger(t, zaptest.Level(zap.InfoLevel)).Sugar()
+			rc := common.NewRunContext(context.Background(), logger, cfg, 0, 0).WithInput(input)
+
+			data, err := os.ReadFile(filepath.Join(dir, input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := puzzle.Parse(rc, common.SplitLines(string(data)))
+			if err != nil {
+				t.Fatalf("Parse returned an error: %v", err)
+			}
+
+			parts := []struct {
+				number   int
+				name     string
+				expected string
+				run      func(*common.RunContext, any) (common.Answer, error)
+			}{
+				{1, "part1", expected.Part1, puzzle.Part1},
+				{2, "part2", expected.Part2, puzzle.Part2},
+			}
+
+			for _, part := range parts {
+				if part.expected == "" {
+					continue
+				}
+				t.Run(part.name, func(t *testing.T) {
+					result, err := part.run(rc.WithPart(part.number), parsed)
+					if err != nil {
+						t.Fatalf("%s returned an error: %v", part.name, err)
+					}
+					if !result.Equal(common.String(part.expected)) {
+						t.Errorf("%s %s: expected %s, got %v", input, part.name, part.expected, result)
+					}
+				})
+			}
+		})
+	}
+}
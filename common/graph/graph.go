@@ -0,0 +1,184 @@
+// Package graph provides searches over graphs which are described only by how
+// to find a node's neighbours, so a grid, a map of nodes or a state space
+// which is worked out as it goes can all be searched the same way.
+//
+// Every search is iterative, so a long path can't overflow the stack.
+package graph
+
+import "container/heap"
+
+// Edge is a step from one node to another. Cost is only used by the
+// weighted searches, Dijkstra and AStar, and BFS treats every edge as a
+// single step.
+type Edge[N comparable] struct {
+	To   N
+	Cost int
+}
+
+// Graph is anything which can list the edges leaving a node. Nodes can be any
+// comparable type, such as a grid.Point, a name or a struct of puzzle state.
+type Graph[N comparable] interface {
+	Neighbours(node N) []Edge[N]
+}
+
+// Func turns a plain function into a Graph, e.g.
+//
+//	g := graph.Func[string](func(node string) []graph.Edge[string] { ... })
+type Func[N comparable] func(node N) []Edge[N]
+
+// Neighbours calls f.
+func (f Func[N]) Neighbours(node N) []Edge[N] {
+	return f(node)
+}
+
+// Paths holds the result of searching from a start node: the distance to
+// every node reached and the route taken to get there.
+type Paths[N comparable] struct {
+	Start N
+	Dist  map[N]int // Distance from Start to each node reached
+	prev  map[N]N   // Node before each node on its shortest path
+}
+
+// newPaths returns Paths holding only the start node.
+func newPaths[N comparable](start N) *Paths[N] {
+	return &Paths[N]{
+		Start: start,
+		Dist:  map[N]int{start: 0},
+		prev:  make(map[N]N),
+	}
+}
+
+// PathTo returns the nodes on the shortest path from Start to the given node,
+// including both ends, or nil if it wasn't reached.
+func (p *Paths[N]) PathTo(node N) []N {
+	if _, reached := p.Dist[node]; !reached {
+		return nil
+	}
+	path := []N{node}
+	for node != p.Start {
+		node = p.prev[node]
+		path = append(path, node)
+	}
+	// Built from the end back to the start, so reverse it
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Furthest returns the reached node furthest from Start and its distance. If
+// several nodes are equally far any one of them is returned.
+func (p *Paths[N]) Furthest() (N, int) {
+	node, furthest := p.Start, 0
+	for n, dist := range p.Dist {
+		if dist > furthest {
+			node, furthest = n, dist
+		}
+	}
+	return node, furthest
+}
+
+// BFS searches outwards from start a step at a time, finding the fewest edges
+// needed to reach every node it can.
+func BFS[N comparable](g Graph[N], start N) *Paths[N] {
+	paths := newPaths(start)
+	queue := []N{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Neighbours(node) {
+			if _, seen := paths.Dist[edge.To]; seen {
+				continue
+			}
+			paths.Dist[edge.To] = paths.Dist[node] + 1
+			paths.prev[edge.To] = node
+			queue = append(queue, edge.To)
+		}
+	}
+	return paths
+}
+
+// Dijkstra finds the cheapest path from start to every node it can reach,
+// adding up the cost of each edge. Costs must not be negative.
+func Dijkstra[N comparable](g Graph[N], start N) *Paths[N] {
+	paths, _, _ := search(g, start, nil, nil)
+	return paths
+}
+
+// AStar finds the cheapest path from start to the first node for which goal
+// returns true. The heuristic guesses the remaining cost from a node. It must
+// never guess more than the real cost, and taking an edge must never lower
+// the guess by more than the edge costs, otherwise the path found might not be
+// the cheapest. The Manhattan distance on a grid is a good example. A nil
+// heuristic makes this a Dijkstra search which stops at the goal.
+//
+// It returns the path, including both ends, and its cost, or false if no goal
+// can be reached.
+func AStar[N comparable](g Graph[N], start N, goal func(N) bool, heuristic func(N) int) ([]N, int, bool) {
+	paths, end, found := search(g, start, goal, heuristic)
+	if !found {
+		return nil, 0, false
+	}
+	return paths.PathTo(end), paths.Dist[end], true
+}
+
+// search runs Dijkstra's algorithm from start, ordering the nodes to visit by
+// their cost so far plus the heuristic. It stops at the first node passing
+// goal, or carries on until every node is reached if goal is nil.
+func search[N comparable](g Graph[N], start N, goal func(N) bool, heuristic func(N) int) (*Paths[N], N, bool) {
+	if heuristic == nil {
+		heuristic = func(N) int { return 0 }
+	}
+
+	paths := newPaths(start)
+	done := make(map[N]bool)
+	queue := &priorityQueue[N]{}
+	heap.Push(queue, item[N]{node: start, priority: heuristic(start)})
+
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(item[N]).node
+		// A node is queued again each time a cheaper path is found, so only
+		// the first time it comes off the queue counts
+		if done[node] {
+			continue
+		}
+		done[node] = true
+		if goal != nil && goal(node) {
+			return paths, node, true
+		}
+
+		for _, edge := range g.Neighbours(node) {
+			cost := paths.Dist[node] + edge.Cost
+			if known, seen := paths.Dist[edge.To]; seen && known <= cost {
+				continue
+			}
+			paths.Dist[edge.To] = cost
+			paths.prev[edge.To] = node
+			heap.Push(queue, item[N]{node: edge.To, priority: cost + heuristic(edge.To)})
+		}
+	}
+
+	var none N
+	return paths, none, false
+}
+
+// item is a node waiting in the priority queue.
+type item[N comparable] struct {
+	node     N
+	priority int
+}
+
+// priorityQueue implements heap.Interface, popping the lowest priority first.
+type priorityQueue[N comparable] []item[N]
+
+func (q priorityQueue[N]) Len() int           { return len(q) }
+func (q priorityQueue[N]) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q priorityQueue[N]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue[N]) Push(x any)        { *q = append(*q, x.(item[N])) }
+
+func (q *priorityQueue[N]) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
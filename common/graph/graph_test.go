@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// adjacency builds a graph from a map of each node's neighbours, with every
+// edge costing 1.
+func adjacency(edges map[string][]string) Graph[string] {
+	return Func[string](func(node string) []Edge[string] {
+		var out []Edge[string]
+		for _, to := range edges[node] {
+			out = append(out, Edge[string]{To: to, Cost: 1})
+		}
+		return out
+	})
+}
+
+// weighted builds a graph from a map of each node's neighbours and their
+// costs.
+func weighted(edges map[string][]Edge[string]) Graph[string] {
+	return Func[string](func(node string) []Edge[string] { return edges[node] })
+}
+
+// TestBFS ensures the fewest steps are found and the path can be followed
+// back.
+func TestBFS(t *testing.T) {
+	g := adjacency(map[string][]string{
+		"a": {"b", "c"},
+		"b": {"d"},
+		"c": {"d"},
+		"d": {"e"},
+	})
+	paths := BFS(g, "a")
+
+	want := map[string]int{"a": 0, "b": 1, "c": 1, "d": 2, "e": 3}
+	if !reflect.DeepEqual(paths.Dist, want) {
+		t.Errorf("Expected distances %v, got %v", want, paths.Dist)
+	}
+	if got := paths.PathTo("e"); !reflect.DeepEqual(got, []string{"a", "b", "d", "e"}) {
+		t.Errorf("Expected path a b d e, got %v", got)
+	}
+	if got := paths.PathTo("z"); got != nil {
+		t.Errorf("Expected no path to an unreached node, got %v", got)
+	}
+	if node, dist := paths.Furthest(); node != "e" || dist != 3 {
+		t.Errorf("Expected e to be furthest at 3, got %s at %d", node, dist)
+	}
+}
+
+// TestBFSLong ensures a long path doesn't overflow the stack.
+func TestBFSLong(t *testing.T) {
+	const length = 1_000_000
+	g := Func[int](func(n int) []Edge[int] {
+		if n == length {
+			return nil
+		}
+		return []Edge[int]{{To: n + 1}}
+	})
+	if _, dist := BFS[int](g, 0).Furthest(); dist != length {
+		t.Errorf("Expected distance %d, got %d", length, dist)
+	}
+}
+
+// TestDijkstra ensures a cheaper path with more edges is preferred.
+func TestDijkstra(t *testing.T) {
+	g := weighted(map[string][]Edge[string]{
+		"a": {{"b", 1}, {"c", 10}},
+		"b": {{"d", 1}},
+		"d": {{"c", 1}},
+	})
+	paths := Dijkstra(g, "a")
+	if paths.Dist["c"] != 3 {
+		t.Errorf("Expected c to cost 3, got %d", paths.Dist["c"])
+	}
+	if got := paths.PathTo("c"); !reflect.DeepEqual(got, []string{"a", "b", "d", "c"}) {
+		t.Errorf("Expected path a b d c, got %v", got)
+	}
+}
+
+// TestAStar ensures the goal is found on an open grid using the Manhattan
+// distance, and that an unreachable goal is reported.
+func TestAStar(t *testing.T) {
+	type point struct{ x, y int }
+	abs := func(n int) int {
+		if n < 0 {
+			return -n
+		}
+		return n
+	}
+	// A 10x10 grid with a wall down column 5 except on the bottom row
+	g := Func[point](func(p point) []Edge[point] {
+		var out []Edge[point]
+		for _, d := range []point{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+			n := point{p.x + d.x, p.y + d.y}
+			if n.x < 0 || n.x > 9 || n.y < 0 || n.y > 9 || (n.x == 5 && n.y < 9) {
+				continue
+			}
+			out = append(out, Edge[point]{To: n, Cost: 1})
+		}
+		return out
+	})
+	end := point{9, 0}
+	goal := func(p point) bool { return p == end }
+	manhattan := func(p point) int { return abs(p.x-end.x) + abs(p.y-end.y) }
+
+	path, cost, found := AStar(g, point{0, 0}, goal, manhattan)
+	if !found || cost != 27 || len(path) != 28 || path[len(path)-1] != end {
+		t.Errorf("Expected a path of cost 27 to %v, got cost %d, %d nodes, found %v", end, cost, len(path), found)
+	}
+
+	if _, _, found := AStar(g, point{0, 0}, func(p point) bool { return p.x == 5 && p.y == 0 }, nil); found {
+		t.Error("Expected a goal inside the wall not to be found")
+	}
+}
+
+// TestComponents ensures separate groups are found and each node appears
+// once.
+func TestComponents(t *testing.T) {
+	g := adjacency(map[string][]string{
+		"a": {"b"}, "b": {"a", "c"}, "c": {"b"},
+		"x": {"y"}, "y": {"x"},
+	})
+	got := Components(g, []string{"a", "x", "c", "z"})
+	want := [][]string{{"a", "b", "c"}, {"x", "y"}, {"z"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestTopologicalSort ensures every edge points forwards in the order, ties
+// keep their given order and cycles are refused.
+func TestTopologicalSort(t *testing.T) {
+	g := adjacency(map[string][]string{
+		"shirt": {"tie", "belt"},
+		"tie":   {"jacket"},
+		"pants": {"shoes", "belt"},
+		"belt":  {"jacket"},
+	})
+	got, err := TopologicalSort(g, []string{"shirt", "pants"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"shirt", "pants", "tie", "shoes", "belt", "jacket"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	cyclic := adjacency(map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}})
+	if _, err := TopologicalSort(cyclic, []string{"a"}); !errors.Is(err, ErrCycle) {
+		t.Errorf("Expected ErrCycle, got %v", err)
+	}
+}
+
+// TestFindCycle ensures the nodes on a cycle are returned in order, including
+// a node pointing at itself.
+func TestFindCycle(t *testing.T) {
+	cases := []struct {
+		name  string
+		edges map[string][]string
+		want  []string
+	}{
+		{"none", map[string][]string{"a": {"b", "c"}, "b": {"c"}}, nil},
+		{"loop", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"d"}, "d": {"b"}}, []string{"b", "c", "d"}},
+		{"self", map[string][]string{"a": {"a"}}, []string{"a"}},
+	}
+	for _, c := range cases {
+		if got := FindCycle(adjacency(c.edges), []string{"a"}); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+// TestFindLoop ensures the tail and loop length of a sequence are found.
+func TestFindLoop(t *testing.T) {
+	// 0 1 2 3 4 5 6 7 3 ... has a tail of 3 and loops every 5 steps
+	next := func(n int) int {
+		if n == 7 {
+			return 3
+		}
+		return n + 1
+	}
+	if tail, length := FindLoop(0, next); tail != 3 || length != 5 {
+		t.Errorf("Expected tail 3 and length 5, got %d and %d", tail, length)
+	}
+}
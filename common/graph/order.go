@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCycle is returned by TopologicalSort when the graph has a cycle, so no
+// order exists.
+var ErrCycle = errors.New("graph has a cycle")
+
+// Components splits the nodes into groups which are connected to each other.
+// Every node reachable from the given nodes is included, and each group lists
+// its nodes in the order they were found. Edges are expected to go both ways;
+// on a one-way graph a node is grouped with the first group which reaches it.
+func Components[N comparable](g Graph[N], nodes []N) [][]N {
+	seen := make(map[N]bool)
+	var groups [][]N
+	for _, start := range nodes {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		group := []N{start}
+		// The group doubles as the queue of nodes to visit
+		for i := 0; i < len(group); i++ {
+			for _, edge := range g.Neighbours(group[i]) {
+				if !seen[edge.To] {
+					seen[edge.To] = true
+					group = append(group, edge.To)
+				}
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// TopologicalSort orders the nodes, and every node reachable from them, so
+// that each edge goes from an earlier node to a later one. Where several
+// nodes could come next they are kept in the order they were given or found.
+// It returns an error wrapping ErrCycle, and listing the cycle, if there is
+// no such order.
+func TopologicalSort[N comparable](g Graph[N], nodes []N) ([]N, error) {
+	// Find every node and count the edges coming into each one
+	all := append([]N(nil), nodes...)
+	incoming := make(map[N]int)
+	seen := make(map[N]bool)
+	for _, node := range nodes {
+		seen[node] = true
+	}
+	for i := 0; i < len(all); i++ {
+		for _, edge := range g.Neighbours(all[i]) {
+			incoming[edge.To]++
+			if !seen[edge.To] {
+				seen[edge.To] = true
+				all = append(all, edge.To)
+			}
+		}
+	}
+
+	// Repeatedly take a node with nothing left pointing at it (Kahn's
+	// algorithm)
+	var queue []N
+	for _, node := range all {
+		if incoming[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+	order := make([]N, 0, len(all))
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		order = append(order, node)
+		for _, edge := range g.Neighbours(node) {
+			incoming[edge.To]--
+			if incoming[edge.To] == 0 {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	// Anything left over is stuck behind a cycle
+	if len(order) < len(all) {
+		return nil, fmt.Errorf("%w: %v", ErrCycle, FindCycle(g, all))
+	}
+	return order, nil
+}
+
+// FindCycle returns the nodes on a cycle reachable from the given nodes, in
+// the order they are followed, or nil if there are no cycles. A node with an
+// edge to itself is a cycle of one.
+func FindCycle[N comparable](g Graph[N], nodes []N) []N {
+	// Depth first search, keeping the current route on a stack. Finding an
+	// edge back to a node still on the route means the route loops.
+	type frame struct {
+		node  N
+		edges []Edge[N]
+	}
+	const (
+		unvisited = iota
+		onRoute
+		finished
+	)
+	state := make(map[N]int)
+
+	for _, start := range nodes {
+		if state[start] != unvisited {
+			continue
+		}
+		state[start] = onRoute
+		stack := []frame{{start, g.Neighbours(start)}}
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if len(top.edges) == 0 {
+				state[top.node] = finished
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			next := top.edges[0].To
+			top.edges = top.edges[1:]
+
+			switch state[next] {
+			case unvisited:
+				state[next] = onRoute
+				stack = append(stack, frame{next, g.Neighbours(next)})
+			case onRoute:
+				// The cycle is the part of the route from next onwards
+				for i := range stack {
+					if stack[i].node == next {
+						cycle := make([]N, 0, len(stack)-i)
+						for _, f := range stack[i:] {
+							cycle = append(cycle, f.node)
+						}
+						return cycle
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// FindLoop follows a sequence where each state leads to exactly one next
+// state, such as walking a map with a fixed list of directions, until a state
+// repeats. It returns the number of steps before the loop starts (the tail)
+// and the number of steps around the loop. The states must eventually repeat,
+// which is true whenever there are only finitely many of them.
+func FindLoop[N comparable](start N, next func(N) N) (tail, length int) {
+	seen := make(map[N]int)
+	state := start
+	for step := 0; ; step++ {
+		if first, repeated := seen[state]; repeated {
+			return first, step - first
+		}
+		seen[state] = step
+		state = next(state)
+	}
+}
@@ -0,0 +1,251 @@
+package grid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Grid is a rectangular 2D grid of cells of any type. Cells are stored row by
+// row in a single slice, and every lookup is bounds checked so solutions don't
+// need to check the edges themselves.
+type Grid[T any] struct {
+	width, height int
+	cells         []T
+}
+
+// New returns a grid of the given size with every cell set to the zero value.
+func New[T any](width, height int) *Grid[T] {
+	if width < 0 || height < 0 {
+		panic(fmt.Sprintf("grid: negative size %dx%d", width, height))
+	}
+	return &Grid[T]{width: width, height: height, cells: make([]T, width*height)}
+}
+
+// FromRows returns a grid holding a copy of the rows. Every row must be the
+// same length.
+func FromRows[T any](rows [][]T) (*Grid[T], error) {
+	if len(rows) == 0 {
+		return New[T](0, 0), nil
+	}
+	g := New[T](len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != g.width {
+			return nil, fmt.Errorf("row %d is %d wide, expected %d", y+1, len(row), g.width)
+		}
+		copy(g.cells[y*g.width:], row)
+	}
+	return g, nil
+}
+
+// FromLines returns a grid of runes from lines of input, the usual way a
+// puzzle gives a map. Empty lines are skipped.
+func FromLines(lines []string) (*Grid[rune], error) {
+	var rows [][]rune
+	for _, line := range lines {
+		if line != "" {
+			rows = append(rows, []rune(line))
+		}
+	}
+	return FromRows(rows)
+}
+
+// Width returns the number of columns.
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+// Height returns the number of rows.
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// InBounds checks if a point is on the grid.
+func (g *Grid[T]) InBounds(p Point) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// Get returns the cell at a point, or the zero value and false if the point
+// is off the grid.
+func (g *Grid[T]) Get(p Point) (T, bool) {
+	if !g.InBounds(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[p.Y*g.width+p.X], true
+}
+
+// At returns the cell at a point, or the zero value if the point is off the
+// grid. Useful when off the grid can be treated like an empty cell.
+func (g *Grid[T]) At(p Point) T {
+	v, _ := g.Get(p)
+	return v
+}
+
+// Set changes the cell at a point, returning false and leaving the grid alone
+// if the point is off the grid.
+func (g *Grid[T]) Set(p Point, v T) bool {
+	if !g.InBounds(p) {
+		return false
+	}
+	g.cells[p.Y*g.width+p.X] = v
+	return true
+}
+
+// All calls yield for every cell, row by row from the top left, until yield
+// returns false.
+func (g *Grid[T]) All(yield func(Point, T) bool) {
+	for i, v := range g.cells {
+		if !yield(Point{i % g.width, i / g.width}, v) {
+			return
+		}
+	}
+}
+
+// Neighbours4 calls yield for each neighbour of p which shares an edge with
+// it and is on the grid, in the order of Directions4, until yield returns
+// false.
+func (g *Grid[T]) Neighbours4(p Point, yield func(Point, T) bool) {
+	g.neighbours(p, Directions4, yield)
+}
+
+// Neighbours8 calls yield for each of the eight neighbours of p, including
+// diagonals, which is on the grid, in the order of Directions8, until yield
+// returns false.
+func (g *Grid[T]) Neighbours8(p Point, yield func(Point, T) bool) {
+	g.neighbours(p, Directions8, yield)
+}
+
+// neighbours calls yield for the cell one step from p in each direction.
+func (g *Grid[T]) neighbours(p Point, directions []Point, yield func(Point, T) bool) {
+	for _, d := range directions {
+		next := p.Add(d)
+		if v, ok := g.Get(next); ok && !yield(next, v) {
+			return
+		}
+	}
+}
+
+// FindAll returns every point holding the value v, row by row from the top
+// left.
+func FindAll[T comparable](g *Grid[T], v T) []Point {
+	var points []Point
+	g.All(func(p Point, cell T) bool {
+		if cell == v {
+			points = append(points, p)
+		}
+		return true
+	})
+	return points
+}
+
+// Find returns the first point holding the value v, reading row by row from
+// the top left, and false if there isn't one.
+func Find[T comparable](g *Grid[T], v T) (Point, bool) {
+	var found Point
+	ok := false
+	g.All(func(p Point, cell T) bool {
+		if cell == v {
+			found, ok = p, true
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// Row returns a copy of row y, or nil if it is off the grid.
+func (g *Grid[T]) Row(y int) []T {
+	if y < 0 || y >= g.height {
+		return nil
+	}
+	row := make([]T, g.width)
+	copy(row, g.cells[y*g.width:])
+	return row
+}
+
+// Column returns a copy of column x, or nil if it is off the grid.
+func (g *Grid[T]) Column(x int) []T {
+	if x < 0 || x >= g.width {
+		return nil
+	}
+	column := make([]T, g.height)
+	for y := range column {
+		column[y] = g.cells[y*g.width+x]
+	}
+	return column
+}
+
+// Rows returns a copy of the grid as a slice of rows.
+func (g *Grid[T]) Rows() [][]T {
+	rows := make([][]T, g.height)
+	for y := range rows {
+		rows[y] = g.Row(y)
+	}
+	return rows
+}
+
+// Clone returns a copy of the grid which can be changed without changing g.
+func (g *Grid[T]) Clone() *Grid[T] {
+	c := New[T](g.width, g.height)
+	copy(c.cells, g.cells)
+	return c
+}
+
+// Transpose returns a new grid flipped along the diagonal from the top left,
+// so rows become columns.
+func (g *Grid[T]) Transpose() *Grid[T] {
+	return g.remap(g.height, g.width, func(p Point) Point { return Point{p.Y, p.X} })
+}
+
+// RotateRight returns a new grid turned a quarter turn clockwise.
+func (g *Grid[T]) RotateRight() *Grid[T] {
+	return g.remap(g.height, g.width, func(p Point) Point { return Point{g.height - 1 - p.Y, p.X} })
+}
+
+// RotateLeft returns a new grid turned a quarter turn anticlockwise.
+func (g *Grid[T]) RotateLeft() *Grid[T] {
+	return g.remap(g.height, g.width, func(p Point) Point { return Point{p.Y, g.width - 1 - p.X} })
+}
+
+// remap returns a new grid of the given size with each cell of g moved to the
+// point returned by to.
+func (g *Grid[T]) remap(width, height int, to func(Point) Point) *Grid[T] {
+	out := New[T](width, height)
+	g.All(func(p Point, v T) bool {
+		out.Set(to(p), v)
+		return true
+	})
+	return out
+}
+
+// Render draws the grid one row per line, using cell to turn each cell into
+// text. Useful for highlighting parts of the grid while debugging.
+func (g *Grid[T]) Render(cell func(Point, T) string) string {
+	var sb strings.Builder
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			sb.WriteString(cell(p, g.cells[y*g.width+x]))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// String draws the grid one row per line. Runes and bytes are drawn as
+// characters, so a grid read from input prints as it was read, and anything
+// else is printed with fmt and separated by spaces.
+func (g *Grid[T]) String() string {
+	return g.Render(func(p Point, v T) string {
+		switch c := any(v).(type) {
+		case rune:
+			return string(c)
+		case byte:
+			return string(rune(c))
+		}
+		if p.X == g.width-1 {
+			return fmt.Sprint(v)
+		}
+		return fmt.Sprint(v) + " "
+	})
+}
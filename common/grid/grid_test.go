@@ -0,0 +1,176 @@
+package grid
+
+import (
+	"reflect"
+	"testing"
+)
+
+// example is a small grid used by the tests below.
+var example = []string{
+	"abc",
+	"def",
+}
+
+// mustLines builds a grid from lines, failing the test on error.
+func mustLines(t *testing.T, lines ...string) *Grid[rune] {
+	t.Helper()
+	g, err := FromLines(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// TestFromLines ensures the size is read, empty lines skipped and ragged rows
+// refused.
+func TestFromLines(t *testing.T) {
+	g := mustLines(t, "abc", "", "def", "")
+	if g.Width() != 3 || g.Height() != 2 {
+		t.Errorf("Expected a 3x2 grid, got %dx%d", g.Width(), g.Height())
+	}
+	if _, err := FromLines([]string{"abc", "de"}); err == nil {
+		t.Error("Expected an error for rows of different lengths")
+	}
+}
+
+// TestGetSet ensures lookups off the grid are refused rather than panicking.
+func TestGetSet(t *testing.T) {
+	g := mustLines(t, example...)
+
+	if v, ok := g.Get(Point{2, 1}); !ok || v != 'f' {
+		t.Errorf("Expected 'f' at (2,1), got %q %v", v, ok)
+	}
+	for _, p := range []Point{{-1, 0}, {3, 0}, {0, -1}, {0, 2}} {
+		if v, ok := g.Get(p); ok || v != 0 {
+			t.Errorf("Expected %v to be off the grid, got %q %v", p, v, ok)
+		}
+		if g.Set(p, 'x') {
+			t.Errorf("Expected setting %v to fail", p)
+		}
+	}
+
+	if !g.Set(Point{0, 1}, 'x') || g.At(Point{0, 1}) != 'x' {
+		t.Errorf("Expected (0,1) to be set to 'x', got %q", g.At(Point{0, 1}))
+	}
+}
+
+// TestNeighbours ensures only neighbours on the grid are visited, in order,
+// and that returning false stops early.
+func TestNeighbours(t *testing.T) {
+	g := mustLines(t, example...)
+
+	collect := func(each func(Point, func(Point, rune) bool), p Point) string {
+		var got []rune
+		each(p, func(_ Point, v rune) bool {
+			got = append(got, v)
+			return true
+		})
+		return string(got)
+	}
+
+	cases := []struct {
+		name string
+		each func(Point, func(Point, rune) bool)
+		p    Point
+		want string
+	}{
+		{"4 corner", g.Neighbours4, Point{0, 0}, "bd"},
+		{"4 middle", g.Neighbours4, Point{1, 1}, "bfd"},
+		{"8 corner", g.Neighbours8, Point{0, 0}, "bed"},
+		{"8 middle", g.Neighbours8, Point{1, 0}, "cfeda"},
+	}
+	for _, c := range cases {
+		if got := collect(c.each, c.p); got != c.want {
+			t.Errorf("%s: expected %q, got %q", c.name, c.want, got)
+		}
+	}
+
+	visits := 0
+	g.Neighbours8(Point{1, 0}, func(Point, rune) bool {
+		visits++
+		return false
+	})
+	if visits != 1 {
+		t.Errorf("Expected to stop after 1 neighbour, visited %d", visits)
+	}
+}
+
+// TestFind ensures every matching point is found in reading order.
+func TestFind(t *testing.T) {
+	g := mustLines(t, ".#.", "#..", "..#")
+
+	want := []Point{{1, 0}, {0, 1}, {2, 2}}
+	if got := FindAll(g, '#'); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if p, ok := Find(g, '#'); !ok || p != want[0] {
+		t.Errorf("Expected %v, got %v %v", want[0], p, ok)
+	}
+	if _, ok := Find(g, 'S'); ok {
+		t.Error("Expected 'S' not to be found")
+	}
+}
+
+// TestViews ensures rows, columns and the reshaped grids hold the right cells.
+func TestViews(t *testing.T) {
+	g := mustLines(t, example...)
+
+	if got := string(g.Row(1)); got != "def" {
+		t.Errorf("Expected row 1 to be \"def\", got %q", got)
+	}
+	if got := string(g.Column(2)); got != "cf" {
+		t.Errorf("Expected column 2 to be \"cf\", got %q", got)
+	}
+	if g.Row(2) != nil || g.Column(-1) != nil {
+		t.Error("Expected nil for rows and columns off the grid")
+	}
+
+	cases := []struct {
+		name string
+		got  *Grid[rune]
+		want string
+	}{
+		{"transpose", g.Transpose(), "ad\nbe\ncf\n"},
+		{"rotate right", g.RotateRight(), "da\neb\nfc\n"},
+		{"rotate left", g.RotateLeft(), "cf\nbe\nad\n"},
+	}
+	for _, c := range cases {
+		if got := c.got.String(); got != c.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", c.name, c.want, got)
+		}
+	}
+}
+
+// TestClone ensures changing a clone leaves the original alone.
+func TestClone(t *testing.T) {
+	g := mustLines(t, example...)
+	c := g.Clone()
+	c.Set(Point{0, 0}, 'x')
+	if g.At(Point{0, 0}) != 'a' {
+		t.Errorf("Expected the original to be unchanged, got %q", g.At(Point{0, 0}))
+	}
+}
+
+// TestString ensures non-character grids are separated by spaces.
+func TestString(t *testing.T) {
+	g, err := FromRows([][]int{{1, 2}, {3, 45}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.String(), "1 2\n3 45\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestPoint ensures the direction helpers turn the right way.
+func TestPoint(t *testing.T) {
+	if Up.TurnRight() != Right || Right.TurnRight() != Down || Up.TurnLeft() != Left {
+		t.Error("Expected quarter turns to follow the compass")
+	}
+	if Up.Reverse() != Down || (Point{3, 4}).Manhattan(Point{0, 0}) != 7 {
+		t.Error("Expected Reverse and Manhattan to match")
+	}
+	if got := (Point{1, 2}).Add(Right).Sub(Point{1, 1}); got != (Point{1, 1}) {
+		t.Errorf("Expected (1,1), got %v", got)
+	}
+}
@@ -0,0 +1,78 @@
+// Package grid provides a generic 2D grid for puzzles whose input is a map of
+// characters, along with the points and directions used to move around it.
+package grid
+
+import "fmt"
+
+// Point is a position on a grid, or the step between two positions. X is the
+// column counting right from 0 and Y is the row counting down from 0, so the
+// top left of the input is {0, 0}.
+type Point struct {
+	X, Y int
+}
+
+// The eight directions as a step of one tile. Up is towards row 0.
+var (
+	Up        = Point{0, -1}
+	Down      = Point{0, 1}
+	Left      = Point{-1, 0}
+	Right     = Point{1, 0}
+	UpLeft    = Point{-1, -1}
+	UpRight   = Point{1, -1}
+	DownLeft  = Point{-1, 1}
+	DownRight = Point{1, 1}
+)
+
+// Directions4 lists the four directions which share an edge with a tile,
+// clockwise from Up.
+var Directions4 = []Point{Up, Right, Down, Left}
+
+// Directions8 lists all eight directions around a tile, including the
+// diagonals, clockwise from Up.
+var Directions8 = []Point{Up, UpRight, Right, DownRight, Down, DownLeft, Left, UpLeft}
+
+// Add returns the point moved by the step q.
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the step which moves q to p.
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Reverse returns the step pointing the opposite way, e.g. Up becomes Down.
+func (p Point) Reverse() Point {
+	return Point{-p.X, -p.Y}
+}
+
+// TurnRight returns the step rotated a quarter turn clockwise, e.g. Up becomes
+// Right.
+func (p Point) TurnRight() Point {
+	return Point{-p.Y, p.X}
+}
+
+// TurnLeft returns the step rotated a quarter turn anticlockwise, e.g. Up
+// becomes Left.
+func (p Point) TurnLeft() Point {
+	return Point{p.Y, -p.X}
+}
+
+// Manhattan returns the number of steps between p and q when moving only up,
+// down, left and right.
+func (p Point) Manhattan(q Point) int {
+	return abs(p.X-q.X) + abs(p.Y-q.Y)
+}
+
+// String prints the point as "(x,y)".
+func (p Point) String() string {
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y)
+}
+
+// abs returns the absolute value of an integer.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -4,38 +4,8 @@ package common
 import (
 	"bufio"
 	"os"
-
-	"gopkg.in/yaml.v2"
 )
 
-// Config
-type Config struct {
-	InputFile string `yaml:"inputFile"`
-	LogLevel  string `yaml:"logLevel"`
-}
-
-// readConfig reads the YAML configuration file and returns the config
-func ReadConfig(paths ...string) (Config, error) {
-	var cfg Config
-	configFilePath := "config.yaml" // Default configuration file
-
-	if len(paths) > 0 {
-		configFilePath = paths[0]
-	}
-
-	data, err := os.ReadFile(configFilePath)
-	if err != nil {
-		return cfg, err
-	}
-
-	err = yaml.Unmarshal(data, &cfg)
-	if err != nil {
-		return cfg, err
-	}
-
-	return cfg, nil
-}
-
 // ReadInputFile reads contents of a file and returns them as a string.
 func ReadInputFile(cfg Config) (string, error) {
 	data, err := os.ReadFile(cfg.InputFile)
@@ -49,9 +19,20 @@ func ReadInputFile(cfg Config) (string, error) {
 	return string(data), nil
 }
 
+// ReadInputLines reads a file and splits it into lines with SplitLines,
+// keeping blank lines between sections.
+func ReadInputLines(cfg Config) ([]string, error) {
+	input, err := ReadInputFile(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return SplitLines(input), nil
+}
+
 // ReadInputFileAs2DSlice reads contents of a file and returns them as a 2D
 // slice of runes. Useful for taking input as a 2D grid with coordinates.
-// Will remove empty lines.
+// Will remove empty lines. See common/grid for a grid with bounds checked
+// lookups and neighbours.
 func ReadInputFileAs2DSlice(cfg Config) ([][]rune, error) {
 	file, err := os.Open(cfg.InputFile)
 	if err != nil {
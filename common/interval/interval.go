@@ -0,0 +1,114 @@
+// Package interval provides arithmetic on ranges of integers, for puzzles
+// where the numbers involved are far too many to handle one at a time.
+//
+// Every range is half-open, so Interval{5, 8} holds 5, 6 and 7 but not 8. This
+// makes lengths and joins simple: {5, 8} has length 8-5 and {5, 8} followed
+// by {8, 10} is {5, 10} with nothing missed or counted twice.
+package interval
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Interval is the range of integers from Start up to, but not including, End.
+// It is empty if End <= Start.
+type Interval struct {
+	Start, End int
+}
+
+// FromLength returns the interval of length numbers starting at start, the
+// way many puzzles describe their ranges.
+func FromLength(start, length int) Interval {
+	return Interval{start, start + length}
+}
+
+// Len returns how many integers the interval holds.
+func (i Interval) Len() int {
+	if i.Empty() {
+		return 0
+	}
+	return i.End - i.Start
+}
+
+// Empty checks if the interval holds no integers.
+func (i Interval) Empty() bool {
+	return i.End <= i.Start
+}
+
+// Contains checks if n is in the interval.
+func (i Interval) Contains(n int) bool {
+	return n >= i.Start && n < i.End
+}
+
+// Overlaps checks if the two intervals share at least one integer.
+func (i Interval) Overlaps(j Interval) bool {
+	return !i.Intersect(j).Empty()
+}
+
+// Intersect returns the integers in both intervals, which is empty if they
+// don't overlap.
+func (i Interval) Intersect(j Interval) Interval {
+	return Interval{max(i.Start, j.Start), min(i.End, j.End)}
+}
+
+// Difference returns the parts of i which aren't in j: nothing, one interval
+// or, if j is in the middle of i, two.
+func (i Interval) Difference(j Interval) []Interval {
+	if i.Empty() {
+		return nil
+	}
+	if !i.Overlaps(j) {
+		return []Interval{i}
+	}
+	var parts []Interval
+	if j.Start > i.Start {
+		parts = append(parts, Interval{i.Start, j.Start})
+	}
+	if j.End < i.End {
+		parts = append(parts, Interval{j.End, i.End})
+	}
+	return parts
+}
+
+// Shift returns the interval moved along by offset.
+func (i Interval) Shift(offset int) Interval {
+	return Interval{i.Start + offset, i.End + offset}
+}
+
+// SplitBy cuts i at every Start and End of the cuts which fall inside it, and
+// returns the pieces in order. Each piece is then either entirely inside or
+// entirely outside each cut, so it can be handled as a whole, such as when
+// mapping a range of numbers through a table of source ranges.
+func (i Interval) SplitBy(cuts []Interval) []Interval {
+	if i.Empty() {
+		return nil
+	}
+	// Collect the points inside i to cut at, in order
+	var points []int
+	for _, cut := range cuts {
+		if cut.Empty() {
+			continue
+		}
+		for _, p := range []int{cut.Start, cut.End} {
+			if p > i.Start && p < i.End {
+				points = append(points, p)
+			}
+		}
+	}
+	slices.Sort(points)
+	points = slices.Compact(points)
+
+	pieces := make([]Interval, 0, len(points)+1)
+	start := i.Start
+	for _, p := range points {
+		pieces = append(pieces, Interval{start, p})
+		start = p
+	}
+	return append(pieces, Interval{start, i.End})
+}
+
+// String prints the interval as "[start,end)".
+func (i Interval) String() string {
+	return fmt.Sprintf("[%d,%d)", i.Start, i.End)
+}
@@ -0,0 +1,114 @@
+package interval
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// TestInterval ensures the basic operations respect the half-open ends.
+func TestInterval(t *testing.T) {
+	i := FromLength(5, 3) // 5, 6 and 7
+	if i != (Interval{5, 8}) || i.Len() != 3 || !i.Contains(7) || i.Contains(8) {
+		t.Errorf("Expected [5,8) holding 5 to 7, got %v", i)
+	}
+	if i.Overlaps(Interval{8, 10}) || !i.Overlaps(Interval{7, 10}) {
+		t.Error("Expected [5,8) to overlap [7,10) but not [8,10)")
+	}
+	if got := i.Intersect(Interval{6, 20}); got != (Interval{6, 8}) {
+		t.Errorf("Expected [6,8), got %v", got)
+	}
+	if (Interval{3, 3}).Len() != 0 || (Interval{4, 2}).Len() != 0 {
+		t.Error("Expected empty intervals to have no length")
+	}
+
+	differences := []struct {
+		j    Interval
+		want []Interval
+	}{
+		{Interval{0, 5}, []Interval{{5, 8}}},
+		{Interval{6, 7}, []Interval{{5, 6}, {7, 8}}},
+		{Interval{0, 7}, []Interval{{7, 8}}},
+		{Interval{0, 10}, nil},
+		{Interval{6, 6}, []Interval{{5, 8}}},
+	}
+	for _, d := range differences {
+		if got := i.Difference(d.j); !reflect.DeepEqual(got, d.want) {
+			t.Errorf("Expected %v - %v to be %v, got %v", i, d.j, d.want, got)
+		}
+	}
+}
+
+// TestSplitBy ensures an interval is cut at every boundary inside it.
+func TestSplitBy(t *testing.T) {
+	got := Interval{0, 10}.SplitBy([]Interval{{2, 4}, {3, 12}, {-5, 0}, {6, 6}})
+	want := []Interval{{0, 2}, {2, 3}, {3, 4}, {4, 10}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := (Interval{0, 10}).SplitBy(nil); !reflect.DeepEqual(got, []Interval{{0, 10}}) {
+		t.Errorf("Expected no cuts to give the whole interval, got %v", got)
+	}
+}
+
+// TestMerge ensures overlapping and touching intervals are joined and empty
+// ones dropped.
+func TestMerge(t *testing.T) {
+	got := Merge(Interval{10, 12}, Interval{1, 3}, Interval{3, 5}, Interval{4, 4}, Interval{2, 4}, Interval{11, 15})
+	want := Set{{1, 5}, {10, 15}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if low, ok := got.Min(); !ok || low != 1 || got.Len() != 9 {
+		t.Errorf("Expected min 1 and 9 integers, got %d %v %d", low, ok, got.Len())
+	}
+	if _, ok := Merge().Min(); ok {
+		t.Error("Expected no minimum for an empty set")
+	}
+}
+
+// TestSetOperations compares the set operations with sets of single integers
+// on lots of small random sets.
+func TestSetOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() Set {
+		var intervals []Interval
+		for n := rng.Intn(4); n > 0; n-- {
+			start := rng.Intn(30)
+			intervals = append(intervals, FromLength(start, rng.Intn(8)))
+		}
+		return Merge(intervals...)
+	}
+	// members lists the integers in a set, checking it is normalised
+	members := func(s Set) map[int]bool {
+		m := make(map[int]bool)
+		for k, i := range s {
+			if i.Empty() || (k > 0 && i.Start <= s[k-1].End) {
+				t.Fatalf("Set %v isn't normalised", s)
+			}
+			for n := i.Start; n < i.End; n++ {
+				m[n] = true
+			}
+		}
+		return m
+	}
+
+	for run := 0; run < 1000; run++ {
+		a, b := random(), random()
+		ma, mb := members(a), members(b)
+		for n := -1; n < 40; n++ {
+			if members(a.Union(b))[n] != (ma[n] || mb[n]) {
+				t.Fatalf("%v union %v is wrong at %d", a, b, n)
+			}
+			if members(a.Intersect(b))[n] != (ma[n] && mb[n]) {
+				t.Fatalf("%v intersect %v is wrong at %d", a, b, n)
+			}
+			if members(a.Difference(b))[n] != (ma[n] && !mb[n]) {
+				t.Fatalf("%v difference %v is wrong at %d", a, b, n)
+			}
+			if a.Contains(n) != ma[n] {
+				t.Fatalf("%v contains is wrong at %d", a, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package interval
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Set is a group of integers held as intervals. A Set made by Merge, or
+// returned by any Set method, is normalised: its intervals are sorted, none
+// are empty and none overlap or touch, so each integer appears once and the
+// lowest is at the start of the first interval.
+type Set []Interval
+
+// Merge returns the normalised Set of integers in any of the intervals.
+func Merge(intervals ...Interval) Set {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, i := range intervals {
+		if !i.Empty() {
+			sorted = append(sorted, i)
+		}
+	}
+	slices.SortFunc(sorted, func(a, b Interval) int { return cmp.Compare(a.Start, b.Start) })
+
+	var set Set
+	for _, i := range sorted {
+		// Join on to the last interval if they overlap or touch
+		if last := len(set) - 1; last >= 0 && i.Start <= set[last].End {
+			set[last].End = max(set[last].End, i.End)
+			continue
+		}
+		set = append(set, i)
+	}
+	return set
+}
+
+// Len returns how many integers are in the set.
+func (s Set) Len() int {
+	total := 0
+	for _, i := range s {
+		total += i.Len()
+	}
+	return total
+}
+
+// Contains checks if n is in the set.
+func (s Set) Contains(n int) bool {
+	for _, i := range s {
+		if i.Contains(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// Min returns the lowest integer in the set, or false if it is empty. The
+// set must be normalised.
+func (s Set) Min() (int, bool) {
+	if len(s) == 0 {
+		return 0, false
+	}
+	return s[0].Start, true
+}
+
+// Union returns the integers in either set.
+func (s Set) Union(t Set) Set {
+	return Merge(append(slices.Clone(s), t...)...)
+}
+
+// Intersect returns the integers in both sets.
+func (s Set) Intersect(t Set) Set {
+	var out []Interval
+	for _, i := range s {
+		for _, j := range t {
+			out = append(out, i.Intersect(j))
+		}
+	}
+	return Merge(out...)
+}
+
+// Difference returns the integers in s which aren't in t.
+func (s Set) Difference(t Set) Set {
+	remaining := slices.Clone(s)
+	for _, j := range t {
+		var next []Interval
+		for _, i := range remaining {
+			next = append(next, i.Difference(j)...)
+		}
+		remaining = next
+	}
+	return Merge(remaining...)
+}
+
+// String prints the set as its intervals, e.g. "{[1,3) [5,6)}".
+func (s Set) String() string {
+	parts := make([]string, len(s))
+	for i, interval := range s {
+		parts[i] = interval.String()
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
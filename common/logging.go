@@ -2,11 +2,24 @@
 package common
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
 )
 
-// InitialiseLogger initalises the Zap logger with the specificed log level
+// InitialiseLogger initalises the Zap logger with the specificed log level,
+// encoding and optional log file. Every logger writes to the same locked
+// stdout, so days running at the same time never interleave their lines. A log
+// file stays open until CloseLogFiles is called.
 func InitialiseLogger(cfg Config) (*zap.SugaredLogger, error) {
 	var logLevel zapcore.Level
 	err := logLevel.UnmarshalText([]byte(cfg.LogLevel))
@@ -14,35 +27,259 @@ func InitialiseLogger(cfg Config) (*zap.SugaredLogger, error) {
 		return nil, err
 	}
 
-	config := zap.Config{
-		Level:            zap.NewAtomicLevelAt(logLevel),
-		Encoding:         "console",
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "time",
-			LevelKey:       "level",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			MessageKey:     "msg",
-			StacktraceKey:  "stacktrace",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapcore.LowercaseLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.StringDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
-	}
-
-	logger, err := config.Build()
-	if err != nil {
-		return nil, err
+	encoderConfig := zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	var encoder zapcore.Encoder
+	switch cfg.LogFormat {
+	case "", "console":
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "logfmt":
+		encoder = newLogfmtEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.LogFormat)
 	}
 
+	// Always log to stdout, and to the log file as well when there is one. The
+	// file does its own locking.
+	var output zapcore.WriteSyncer = stdoutSink
+	if cfg.LogFile != "" {
+		file, err := openRotatingFile(cfg.LogFile, int64(cfg.LogMaxSize)<<20, cfg.LogMaxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("opening log file: %w", err)
+		}
+		output = zapcore.NewMultiWriteSyncer(output, file)
+	}
+
+	core := zapcore.NewCore(encoder, output, zap.NewAtomicLevelAt(logLevel))
+	logger := zap.New(core, zap.AddCaller(), zap.ErrorOutput(stderrSink))
+
 	// Using the sugared logger
 	sugar := logger.Sugar()
 	sugar.Debug("Logger construction successful")
 
-	zap.ReplaceGlobals(logger) // Replace the global logger
 	return sugar, nil
 }
+
+// stdoutSink and stderrSink are shared by every logger, so only one entry is
+// written to each at a time however many loggers there are.
+var (
+	stdoutSink = zapcore.Lock(os.Stdout)
+	stderrSink = zapcore.Lock(os.Stderr)
+)
+
+// logfmtEncoder writes each entry as key=value pairs. It lets the JSON
+// encoder do the work and rewrites its output, so every field type zap
+// supports is handled the same way as in JSON.
+type logfmtEncoder struct {
+	zapcore.Encoder
+}
+
+// logfmtPool provides the buffers logfmt entries are written to.
+var logfmtPool = buffer.NewPool()
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return logfmtEncoder{zapcore.NewJSONEncoder(cfg)}
+}
+
+// Clone copies the encoder, including any fields added with With.
+func (e logfmtEncoder) Clone() zapcore.Encoder {
+	return logfmtEncoder{e.Encoder.Clone()}
+}
+
+// EncodeEntry encodes an entry as JSON and converts it to logfmt. Nested
+// objects and arrays are kept as quoted JSON.
+func (e logfmtEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	encoded, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer encoded.Free()
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded.Bytes()))
+	if _, err := decoder.Token(); err != nil { // Opening brace
+		return nil, err
+	}
+
+	line := logfmtPool.Get()
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			line.Free()
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			line.Free()
+			return nil, err
+		}
+
+		if line.Len() > 0 {
+			line.AppendByte(' ')
+		}
+		line.AppendString(fmt.Sprint(key))
+		line.AppendByte('=')
+		line.AppendString(logfmtValue(value))
+	}
+	line.AppendString(zapcore.DefaultLineEnding)
+	return line, nil
+}
+
+// logfmtValue formats a JSON value for logfmt, quoting it when needed.
+func logfmtValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		s = string(raw) // Numbers, booleans, null and nested values
+		if raw[0] != '{' && raw[0] != '[' {
+			return s
+		}
+	}
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		quoted, _ := json.Marshal(s)
+		return string(quoted)
+	}
+	return s
+}
+
+// rotatingFile is a log file which is renamed to NAME.1 once it reaches its
+// maximum size, with older files shifted along to NAME.2 and so on.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex // Loggers for days running at the same time share the file
+	file *os.File
+	size int64
+}
+
+// rotatingFiles are the log files already open by path, so every logger
+// writing to the same file shares it and its size.
+var (
+	rotatingFiles   = make(map[string]*rotatingFile)
+	rotatingFilesMu sync.Mutex
+)
+
+// openRotatingFile opens a log file for appending, or returns the already
+// open file for the same path. Two days can't rotate one file differently, so
+// asking for an open file with other settings is an error.
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	rotatingFilesMu.Lock()
+	defer rotatingFilesMu.Unlock()
+
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := rotatingFiles[key]; ok {
+		if r.maxSize != maxSize || r.maxBackups != maxBackups {
+			return nil, fmt.Errorf("log file %s is already open with a maximum size of %d bytes and %d backups", path, r.maxSize, r.maxBackups)
+		}
+		return r, nil
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	rotatingFiles[key] = r
+	return r, nil
+}
+
+// CloseLogFiles closes every log file opened by InitialiseLogger, once the
+// loggers writing to them are finished with. Loggers made afterwards open
+// their files again.
+func CloseLogFiles() error {
+	rotatingFilesMu.Lock()
+	defer rotatingFilesMu.Unlock()
+
+	var errs []error
+	for key, r := range rotatingFiles {
+		errs = append(errs, r.Close())
+		delete(rotatingFiles, key)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write writes to the log file, rotating it first if p would take it over
+// its maximum size. A single entry larger than the maximum is still written.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate closes the current file, shifts the backups along and starts a new
+// file.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	// The oldest backup falls off the end
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.maxBackups > 0 {
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+// Close flushes the log file to disk and closes it.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Sync(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// Sync flushes the log file to disk.
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Sync()
+}
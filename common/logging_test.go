@@ -0,0 +1,78 @@
+package common
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLogfmtEncoder ensures entries and their fields are written as logfmt.
+func TestLogfmtEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := newLogfmtEncoder(zapcore.EncoderConfig{
+		LevelKey:    "level",
+		MessageKey:  "msg",
+		EncodeLevel: zapcore.LowercaseLevelEncoder,
+	})
+	logger := zap.New(zapcore.NewCore(encoder, zapcore.AddSync(&buf), zap.DebugLevel)).Sugar()
+
+	logger.With("year", 2023, "day", 7).Infow("Part 1: 6440", "input", "test_input.txt", "ok", true, "empty", "")
+
+	want := `level=info msg="Part 1: 6440" year=2023 day=7 input=test_input.txt ok=true empty=""` + "\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+// TestRotatingFile ensures the log file is rotated at its maximum size and
+// only the configured number of backups are kept.
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aoc.log")
+	file, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("openRotatingFile returned an error: %v", err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write returned an error: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for name, contents := range want {
+		data, err := os.ReadFile(name)
+		if err != nil || string(data) != contents {
+			t.Errorf("Expected %s to hold %q, got %q (err %v)", filepath.Base(name), contents, data, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Errorf("Expected only 2 backups to be kept")
+	}
+
+	if again, _ := openRotatingFile(path, 10, 2); again != file {
+		t.Errorf("Expected the open file to be shared")
+	}
+	if _, err := openRotatingFile(path, 20, 2); err == nil {
+		t.Errorf("Expected opening the file with different settings to fail")
+	}
+
+	if err := CloseLogFiles(); err != nil {
+		t.Fatalf("CloseLogFiles returned an error: %v", err)
+	}
+	if _, err := file.Write([]byte("fifth\n")); err == nil {
+		t.Errorf("Expected writing to a closed file to fail")
+	}
+	if reopened, _ := openRotatingFile(path, 10, 2); reopened == file {
+		t.Errorf("Expected the file to be opened again after closing")
+	}
+	CloseLogFiles()
+}
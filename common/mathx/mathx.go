@@ -0,0 +1,100 @@
+// Package mathx provides the number theory puzzles keep needing, such as GCD,
+// LCM, modular inverses and the Chinese Remainder Theorem, without silently
+// overflowing. Functions whose result might not fit say so, and have a
+// math/big version to fall back on.
+package mathx
+
+import "math/big"
+
+// Integer is any signed integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Abs returns the absolute value of n. The lowest value of a type has no
+// positive version and is returned unchanged.
+func Abs[T Integer](n T) T {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Add returns a + b, and false if the result overflowed.
+func Add[T Integer](a, b T) (T, bool) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
+		return c, false
+	}
+	return c, true
+}
+
+// Mul returns a * b, and false if the result overflowed.
+func Mul[T Integer](a, b T) (T, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	// Dividing back finds most overflows. Multiplying the lowest value by -1
+	// gives itself back, which dividing can't spot.
+	if c/b != a || (a == -1 && c == b) || (b == -1 && c == a) {
+		return c, false
+	}
+	return c, true
+}
+
+// GCD returns the greatest common divisor of the numbers, which is never
+// negative. The GCD of no numbers, or only zeros, is 0.
+func GCD[T Integer](nums ...T) T {
+	var g T
+	for _, n := range nums {
+		a, b := g, Abs(n)
+		for b != 0 {
+			a, b = b, a%b
+		}
+		g = a
+	}
+	return g
+}
+
+// LCM returns the least common multiple of the numbers, which is never
+// negative, and false if it is too big for T. Use BigLCM then instead. The
+// LCM of no numbers is 1, and of any set including 0 is 0.
+func LCM[T Integer](nums ...T) (T, bool) {
+	var l T = 1
+	for _, n := range nums {
+		n = Abs(n)
+		if n == 0 {
+			return 0, true
+		}
+		if n < 0 {
+			// Abs of the lowest value is still negative
+			return 0, false
+		}
+		// Divide first so the multiply is as small as it can be
+		var ok bool
+		l, ok = Mul(l/GCD(l, n), n)
+		if !ok {
+			return 0, false
+		}
+	}
+	return l, true
+}
+
+// BigLCM returns the least common multiple of the numbers as a big.Int, so it
+// can't overflow.
+func BigLCM[T Integer](nums ...T) *big.Int {
+	l := big.NewInt(1)
+	var g, n big.Int
+	for _, v := range nums {
+		n.SetInt64(int64(v))
+		n.Abs(&n)
+		if n.Sign() == 0 {
+			return new(big.Int)
+		}
+		g.GCD(nil, nil, l, &n)
+		l.Div(l, &g)
+		l.Mul(l, &n)
+	}
+	return l
+}
@@ -0,0 +1,209 @@
+package mathx
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// TestOverflow ensures Add and Mul spot results which don't fit.
+func TestOverflow(t *testing.T) {
+	if _, ok := Add(math.MaxInt64-1, 1); !ok {
+		t.Error("Expected MaxInt64-1 + 1 to fit")
+	}
+	if _, ok := Add(math.MaxInt64, 1); ok {
+		t.Error("Expected MaxInt64 + 1 to overflow")
+	}
+	if _, ok := Add(math.MinInt64, -1); ok {
+		t.Error("Expected MinInt64 - 1 to overflow")
+	}
+
+	cases := []struct {
+		a, b int64
+		ok   bool
+	}{
+		{3037000499, 3037000499, true},
+		{3037000500, 3037000500, false},
+		{-1, math.MinInt64, false},
+		{math.MinInt64, -1, false},
+		{-1, math.MaxInt64, true},
+		{0, math.MinInt64, true},
+	}
+	for _, c := range cases {
+		if _, ok := Mul(c.a, c.b); ok != c.ok {
+			t.Errorf("Expected Mul(%d, %d) ok to be %v", c.a, c.b, c.ok)
+		}
+	}
+	if _, ok := Mul[int8](16, 8); ok {
+		t.Error("Expected 16 * 8 to overflow an int8")
+	}
+}
+
+// TestGCDLCM ensures the results match known values and overflow falls back.
+func TestGCDLCM(t *testing.T) {
+	if got := GCD(12, -18, 30); got != 6 {
+		t.Errorf("Expected GCD 6, got %d", got)
+	}
+	if got := GCD[int](); got != 0 {
+		t.Errorf("Expected GCD of nothing to be 0, got %d", got)
+	}
+	if got, ok := LCM(4, 6, -10); !ok || got != 60 {
+		t.Errorf("Expected LCM 60, got %d %v", got, ok)
+	}
+	if got, ok := LCM(4, 0); !ok || got != 0 {
+		t.Errorf("Expected LCM with 0 to be 0, got %d %v", got, ok)
+	}
+
+	// The first 20 primes multiply to more than an int64 can hold
+	primes := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71}
+	if _, ok := LCM(primes...); ok {
+		t.Error("Expected the LCM of 20 primes to overflow")
+	}
+	want, _ := new(big.Int).SetString("557940830126698960967415390", 10)
+	if got := BigLCM(primes...); got.Cmp(want) != 0 {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// LCM and BigLCM agree whenever LCM fits
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		nums := []int{rng.Intn(1e6) + 1, rng.Intn(1e6) + 1, rng.Intn(1e6) + 1}
+		got, ok := LCM(nums...)
+		large := BigLCM(nums...)
+		if ok != large.IsInt64() || (ok && int64(got) != large.Int64()) {
+			t.Fatalf("LCM%v gave %d %v, BigLCM gave %v", nums, got, ok, large)
+		}
+	}
+}
+
+// TestModInverse ensures the inverse multiplies back to 1 and missing ones
+// are reported.
+func TestModInverse(t *testing.T) {
+	g, x, y := ExtendedGCD(240, 46)
+	if g != 2 || 240*x+46*y != 2 {
+		t.Errorf("Expected 240x + 46y = 2, got g %d x %d y %d", g, x, y)
+	}
+	for _, c := range []struct{ a, m int }{{3, 11}, {-3, 11}, {10, 17}, {1, 1}} {
+		inv, ok := ModInverse(c.a, c.m)
+		if !ok || inv < 0 || inv >= c.m || Mod(c.a*inv, c.m) != Mod(1, c.m) {
+			t.Errorf("Expected an inverse of %d mod %d, got %d %v", c.a, c.m, inv, ok)
+		}
+	}
+	if _, ok := ModInverse(6, 9); ok {
+		t.Error("Expected 6 to have no inverse mod 9")
+	}
+}
+
+// TestCRT ensures congruences are combined, including moduli which share
+// factors, and contradictions are reported.
+func TestCRT(t *testing.T) {
+	cases := []struct {
+		in   []Congruence
+		want Congruence
+	}{
+		{[]Congruence{{2, 3}, {3, 5}, {2, 7}}, Congruence{23, 105}},
+		{[]Congruence{{3, 4}, {5, 6}}, Congruence{11, 12}},
+		{[]Congruence{{0, 6}, {0, 10}}, Congruence{0, 30}},
+		{[]Congruence{{-1, 5}}, Congruence{4, 5}},
+		{nil, Congruence{0, 1}},
+	}
+	for _, c := range cases {
+		got, err := CRT(c.in...)
+		if err != nil || got != c.want {
+			t.Errorf("Expected %v to combine to %v, got %v %v", c.in, c.want, got, err)
+		}
+	}
+
+	if _, err := CRT(Congruence{1, 4}, Congruence{2, 6}); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Expected ErrNoSolution, got %v", err)
+	}
+	if _, err := CRT(Congruence{1, 0}); err == nil {
+		t.Error("Expected an error for a zero modulus")
+	}
+
+	// Moduli multiplying past an int64 need BigCRT
+	huge := []Congruence{{1, 1_000_000_007}, {2, 998_244_353}, {3, 1_000_000_009}}
+	if _, err := CRT(huge...); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	residue, modulus, err := BigCRT(huge...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range huge {
+		m := big.NewInt(int64(c.Modulus))
+		if new(big.Int).Mod(residue, m).Int64() != int64(c.Residue) {
+			t.Errorf("Expected %v mod %v to be %d", residue, modulus, c.Residue)
+		}
+	}
+}
+
+// TestBigMerge ensures folding congruences in one at a time matches BigCRT
+// and leaves the earlier result alone.
+func TestBigMerge(t *testing.T) {
+	residue, modulus := big.NewInt(2), big.NewInt(3)
+	merged, combined, err := BigMerge(residue, modulus, Congruence{3, 4})
+	if err != nil || merged.Int64() != 11 || combined.Int64() != 12 {
+		t.Errorf("Expected x ≡ 11 (mod 12), got %v %v %v", merged, combined, err)
+	}
+	if residue.Int64() != 2 || modulus.Int64() != 3 {
+		t.Errorf("Expected the inputs to be unchanged, got %v and %v", residue, modulus)
+	}
+	if _, _, err := BigMerge(merged, combined, Congruence{0, 6}); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Expected ErrNoSolution, got %v", err)
+	}
+}
+
+// TestIsqrt ensures the floor of the square root is exact around perfect
+// squares and for the biggest ints.
+func TestIsqrt(t *testing.T) {
+	for r := 0; r < 2000; r++ {
+		if got := Isqrt(r * r); got != r {
+			t.Fatalf("Expected Isqrt(%d) = %d, got %d", r*r, r, got)
+		}
+		if r > 0 {
+			if got := Isqrt(r*r - 1); got != r-1 {
+				t.Fatalf("Expected Isqrt(%d) = %d, got %d", r*r-1, r-1, got)
+			}
+		}
+	}
+	if got := Isqrt(math.MaxInt64); got != 3037000499 {
+		t.Errorf("Expected Isqrt(MaxInt64) = 3037000499, got %d", got)
+	}
+}
+
+// TestQuadraticBelow compares the solver with checking every x, including
+// roots which land exactly on an integer.
+func TestQuadraticBelow(t *testing.T) {
+	// The race from the puzzle example: hold for 2 to 5 of 7ms to beat 9mm
+	if lo, hi, err := QuadraticBelow(1, -7, 9); err != nil || lo != 2 || hi != 6 {
+		t.Errorf("Expected [2,6), got [%d,%d) %v", lo, hi, err)
+	}
+	// Holding 10 or 20 of 30ms exactly ties the record of 200mm
+	if lo, hi, err := QuadraticBelow(1, -30, 200); err != nil || lo != 11 || hi != 20 {
+		t.Errorf("Expected [11,20), got [%d,%d) %v", lo, hi, err)
+	}
+
+	for a := 1; a <= 4; a++ {
+		for b := -40; b <= 40; b++ {
+			for c := -40; c <= 40; c++ {
+				lo, hi, err := QuadraticBelow(a, b, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for x := -100; x <= 100; x++ {
+					inside := x >= lo && x < hi
+					if below := a*x*x+b*x+c < 0; below != inside {
+						t.Fatalf("%dx² + %dx + %d: got [%d,%d) but x=%d is below zero: %v", a, b, c, lo, hi, x, below)
+					}
+				}
+			}
+		}
+	}
+
+	if _, _, err := QuadraticBelow(0, 1, 1); err == nil {
+		t.Error("Expected an error for a zero x² coefficient")
+	}
+}
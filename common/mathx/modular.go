@@ -0,0 +1,130 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrNoSolution is returned by CRT when the congruences contradict each
+// other.
+var ErrNoSolution = errors.New("no number satisfies every congruence")
+
+// ErrOverflow is returned when a result is too big for an int.
+var ErrOverflow = errors.New("result overflows int")
+
+// Mod returns a modulo m, which unlike % is never negative for a positive m.
+func Mod[T Integer](a, m T) T {
+	r := a % m
+	if r < 0 {
+		r += Abs(m)
+	}
+	return r
+}
+
+// ExtendedGCD returns the greatest common divisor g of a and b along with x
+// and y such that a*x + b*y = g (Bézout's identity).
+func ExtendedGCD(a, b int) (g, x, y int) {
+	oldR, r := a, b
+	oldX, x := 1, 0
+	oldY, y := 0, 1
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldX, x = x, oldX-q*x
+		oldY, y = y, oldY-q*y
+	}
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
+
+// ModInverse returns x such that a*x is 1 modulo m, in the range [0, m), and
+// false if there isn't one because a and m share a factor.
+func ModInverse(a, m int) (int, bool) {
+	if m <= 0 {
+		return 0, false
+	}
+	g, x, _ := ExtendedGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, false
+	}
+	return Mod(x, m), true
+}
+
+// Congruence is the condition that a number leaves Residue when divided by
+// Modulus, written x ≡ Residue (mod Modulus).
+type Congruence struct {
+	Residue, Modulus int
+}
+
+// String prints the congruence as "x ≡ r (mod m)".
+func (c Congruence) String() string {
+	return fmt.Sprintf("x ≡ %d (mod %d)", c.Residue, c.Modulus)
+}
+
+// CRT combines congruences using the Chinese Remainder Theorem, returning the
+// single congruence which holds exactly when all of them do. Its Residue is
+// the smallest non-negative number satisfying them all. Unlike the textbook
+// version the moduli don't need to be coprime, but then the congruences might
+// contradict each other, which returns ErrNoSolution. Every Modulus must be
+// positive. If the combined modulus doesn't fit in an int ErrOverflow is
+// returned, and BigCRT can be used instead.
+func CRT(congruences ...Congruence) (Congruence, error) {
+	residue, modulus, err := BigCRT(congruences...)
+	if err != nil {
+		return Congruence{}, err
+	}
+	if !residue.IsInt64() || !modulus.IsInt64() {
+		return Congruence{}, ErrOverflow
+	}
+	return Congruence{Residue: int(residue.Int64()), Modulus: int(modulus.Int64())}, nil
+}
+
+// BigCRT is CRT using big.Int, so the combined modulus can be any size.
+func BigCRT(congruences ...Congruence) (residue, modulus *big.Int, err error) {
+	residue, modulus = big.NewInt(0), big.NewInt(1)
+	for _, c := range congruences {
+		if residue, modulus, err = BigMerge(residue, modulus, c); err != nil {
+			return nil, nil, err
+		}
+	}
+	return residue, modulus, nil
+}
+
+// BigMerge folds one more congruence into x ≡ residue (mod modulus), the
+// result of combining earlier ones, as BigCRT does for each in turn. This lets
+// a search trying several choices of congruence share the work for the ones
+// it has already chosen. The residue and modulus passed in aren't changed.
+func BigMerge(residue, modulus *big.Int, c Congruence) (*big.Int, *big.Int, error) {
+	if c.Modulus <= 0 {
+		return nil, nil, fmt.Errorf("modulus must be positive, got %d", c.Modulus)
+	}
+	var r2, m2, g, diff, rem, k, step big.Int
+	m2.SetInt64(int64(c.Modulus))
+	r2.SetInt64(int64(c.Residue))
+	r2.Mod(&r2, &m2)
+
+	// Solve residue + modulus*k ≡ r2 (mod m2) for k. Only possible if the
+	// gap between the residues is a multiple of gcd(modulus, m2).
+	g.GCD(nil, nil, modulus, &m2)
+	diff.Sub(&r2, residue)
+	if rem.Mod(&diff, &g); rem.Sign() != 0 {
+		return nil, nil, fmt.Errorf("%w: %v", ErrNoSolution, c)
+	}
+	diff.Div(&diff, &g)
+	step.Div(&m2, &g) // k repeats every m2/g
+	k.Div(modulus, &g)
+	if k.ModInverse(&k, &step) == nil {
+		// step is 1, so every k works
+		k.SetInt64(0)
+	}
+	k.Mul(&k, &diff)
+	k.Mod(&k, &step)
+
+	merged := new(big.Int).Mul(&k, modulus)
+	merged.Add(merged, residue)
+	combined := new(big.Int).Mul(modulus, &step)
+	return merged.Mod(merged, combined), combined, nil
+}
@@ -0,0 +1,90 @@
+package mathx
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Isqrt returns the largest integer whose square is at most n. It panics if n
+// is negative.
+func Isqrt(n int) int {
+	if n < 0 {
+		panic(fmt.Sprintf("mathx: square root of negative number %d", n))
+	}
+	// A float gets close, then correct the last step or two exactly
+	r := int(math.Sqrt(float64(n)))
+	for r > 0 && r > n/r {
+		r--
+	}
+	for r+1 <= n/(r+1) {
+		r++
+	}
+	return r
+}
+
+// QuadraticBelow returns the integers x for which a*x*x + b*x + c < 0, for a
+// positive a, as the half-open range [lo, hi). The range is empty (lo == hi)
+// when no integer makes it negative. A root which is exactly an integer makes
+// the value zero, not negative, so it is left out.
+//
+// For example, holding a race car's button for x of t milliseconds beats a
+// record of d when (t-x)*x > d, which is x*x - t*x + d < 0.
+//
+// The sums are done with big.Int so they can't overflow, and an error is
+// returned if a isn't positive or the answer doesn't fit in an int.
+func QuadraticBelow(a, b, c int) (lo, hi int, err error) {
+	if a <= 0 {
+		return 0, 0, fmt.Errorf("quadratic needs a positive x² coefficient, got %d", a)
+	}
+	A, B, C := big.NewInt(int64(a)), big.NewInt(int64(b)), big.NewInt(int64(c))
+
+	// f(x) < 0 only between the roots (-b ± √(b²-4ac)) / 2a, so there is
+	// nothing to find unless the discriminant is positive
+	disc := new(big.Int).Mul(B, B)
+	disc.Sub(disc, new(big.Int).Mul(big.NewInt(4), new(big.Int).Mul(A, C)))
+	if disc.Sign() <= 0 {
+		return 0, 0, nil
+	}
+	root := new(big.Int).Sqrt(disc)
+
+	// f is evaluated exactly to nudge the rounded roots to the right integer
+	negative := func(x *big.Int) bool {
+		v := new(big.Int).Mul(A, x)
+		v.Add(v, B)
+		v.Mul(v, x)
+		v.Add(v, C)
+		return v.Sign() < 0
+	}
+	one := big.NewInt(1)
+	twoA := new(big.Int).Mul(big.NewInt(2), A)
+	negB := new(big.Int).Neg(B)
+
+	// The first negative x is just above the lower root. Rounding the root
+	// down can leave the estimate one short, but no further.
+	low := new(big.Int).Sub(negB, root)
+	low.Div(low, twoA)
+	if !negative(low) {
+		low.Add(low, one)
+	}
+	if !negative(low) {
+		// The roots are too close together to have an integer between
+		return 0, 0, nil
+	}
+
+	// Likewise the first x which isn't negative again is at or just above the
+	// upper root, which the estimate can undershoot by up to two
+	high := new(big.Int).Add(negB, root)
+	high.Div(high, twoA)
+	if high.Cmp(low) < 0 {
+		high.Set(low)
+	}
+	for negative(high) {
+		high.Add(high, one)
+	}
+
+	if !low.IsInt64() || !high.IsInt64() {
+		return 0, 0, ErrOverflow
+	}
+	return int(low.Int64()), int(high.Int64()), nil
+}
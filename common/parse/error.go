@@ -0,0 +1,39 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a problem with the input at a known position. It prints as
+// "file:line:col: problem", leaving out any part of the position which isn't
+// known, the same way the Go compiler reports errors.
+type Error struct {
+	File string
+	Line int // Counting from 1, or 0 if not known
+	Col  int // Counting from 1, or 0 if not known
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	var pos []string
+	if e.File != "" {
+		pos = append(pos, e.File)
+	}
+	if e.Line > 0 {
+		pos = append(pos, fmt.Sprint(e.Line))
+		if e.Col > 0 {
+			pos = append(pos, fmt.Sprint(e.Col))
+		}
+	}
+	if len(pos) == 0 {
+		return e.Err.Error()
+	}
+	return strings.Join(pos, ":") + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
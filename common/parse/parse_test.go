@@ -0,0 +1,146 @@
+package parse
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// span returns a span for line 3 of test.txt.
+func span(text string) Span {
+	return Span{File: "test.txt", Line: 3, Col: 1, Text: text}
+}
+
+// TestError ensures the position is printed like a compiler error and the
+// cause can be unwrapped.
+func TestError(t *testing.T) {
+	cause := errors.New("bad")
+	cases := []struct {
+		err  *Error
+		want string
+	}{
+		{&Error{File: "in.txt", Line: 2, Col: 5, Err: cause}, "in.txt:2:5: bad"},
+		{&Error{File: "in.txt", Line: 2, Err: cause}, "in.txt:2: bad"},
+		{&Error{Line: 2, Col: 5, Err: cause}, "2:5: bad"},
+		{&Error{Err: cause}, "bad"},
+	}
+	for _, c := range cases {
+		if got := c.err.Error(); got != c.want {
+			t.Errorf("Expected %q, got %q", c.want, got)
+		}
+		if !errors.Is(c.err, cause) {
+			t.Errorf("Expected %q to unwrap to the cause", c.want)
+		}
+	}
+}
+
+// TestSpan ensures splitting a span keeps the columns of each piece.
+func TestSpan(t *testing.T) {
+	s := span("Card  1: 41 48 | 83 86")
+
+	key, value, err := s.KeyValue(":")
+	if err != nil || key != "Card  1" || value.Text != "41 48 | 83 86" || value.Col != 10 {
+		t.Errorf("Expected key \"Card  1\" and value at col 10, got %q %+v %v", key, value, err)
+	}
+
+	parts := value.Split("|")
+	var cols []int
+	for _, f := range parts[1].Fields() {
+		cols = append(cols, f.Col)
+	}
+	if !reflect.DeepEqual(cols, []int{18, 21}) {
+		t.Errorf("Expected fields at cols [18 21], got %v", cols)
+	}
+
+	if _, _, err := span("no separator").KeyValue(":"); err == nil {
+		t.Error("Expected an error for a missing separator")
+	}
+}
+
+// TestInts ensures numbers are pulled out of text and bad numbers are
+// reported where they are.
+func TestInts(t *testing.T) {
+	got, err := span("x=3, y=-12, z=+4").Ints()
+	if err != nil || !reflect.DeepEqual(got, []int{3, -12, 4}) {
+		t.Errorf("Expected [3 -12 4], got %v %v", got, err)
+	}
+
+	got, err = span(" 1  2 3 ").FieldInts()
+	if err != nil || !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v %v", got, err)
+	}
+
+	_, err = span("1 2 x3").FieldInts()
+	if err == nil || err.Error() != `test.txt:3:5: expected a number, got "x3"` {
+		t.Errorf("Expected an error at column 5, got %v", err)
+	}
+
+	_, err = span("1 99999999999999999999").Ints()
+	if err == nil || err.Error() != "test.txt:3:3: number 99999999999999999999 is too big" {
+		t.Errorf("Expected an overflow error at column 3, got %v", err)
+	}
+}
+
+// TestSections ensures lines are grouped between blank lines.
+func TestSections(t *testing.T) {
+	lines := Lines("in.txt", []string{"", "a", "b", "", "", "c", " "})
+	sections := Sections(lines)
+	if len(sections) != 2 || len(sections[0]) != 2 || len(sections[1]) != 1 {
+		t.Fatalf("Expected sections of 2 and 1 lines, got %v", sections)
+	}
+	if sections[1][0].Text != "c" || sections[1][0].Line != 6 {
+		t.Errorf("Expected \"c\" on line 6, got %+v", sections[1][0])
+	}
+}
+
+// TestPattern ensures captures are filled and mismatches report where they
+// happened.
+func TestPattern(t *testing.T) {
+	p := MustCompile("{node} = ({left}, {right})")
+	m, err := p.Match(span("AAA = (BBB, CCC)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Text("node") != "AAA" || m.Text("left") != "BBB" || m.Text("right") != "CCC" {
+		t.Errorf("Expected AAA BBB CCC, got %s %s %s", m.Text("node"), m.Text("left"), m.Text("right"))
+	}
+	if m.Span("right").Col != 13 {
+		t.Errorf("Expected right at column 13, got %d", m.Span("right").Col)
+	}
+
+	game := MustCompile("Game {id:int}: {rest}")
+	m, err = game.Match(span("Game  12: 3 blue"))
+	if err != nil || m.Int("id") != 12 || m.Text("rest") != "3 blue" {
+		t.Errorf("Expected id 12 and rest \"3 blue\", got %v", err)
+	}
+
+	failures := []struct {
+		pattern *Pattern
+		text    string
+		want    string
+	}{
+		{p, "AAA = (BBB CCC)", `test.txt:3:8: expected ", " after left, got "BBB CCC)"`},
+		{p, "AAA - (BBB, CCC)", `test.txt:3:1: expected " = (" after node, got "AAA - (BBB, CCC)"`},
+		{p, "AAA = (BBB, CCC) x", `test.txt:3:17: unexpected " x" at end of line`},
+		{game, "Game x: 3 blue", `test.txt:3:6: expected a number for id, got "x: 3 blue"`},
+	}
+	for _, f := range failures {
+		_, err := f.pattern.Match(span(f.text))
+		if err == nil || err.Error() != f.want {
+			t.Errorf("%q: expected error %q, got %v", f.text, f.want, err)
+		}
+	}
+}
+
+// TestCompile ensures bad templates are refused.
+func TestCompile(t *testing.T) {
+	for _, template := range []string{"{a", "a}", "{}", "{a}{b}", "{a} {a}", "{a:float}"} {
+		if _, err := Compile(template); err == nil {
+			t.Errorf("Expected %q to be refused", template)
+		}
+	}
+	m, err := MustCompile("{{{a}}}").Match(span("{x}"))
+	if err != nil || m.Text("a") != "x" {
+		t.Errorf("Expected doubled braces to be literal, got %v", err)
+	}
+}
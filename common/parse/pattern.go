@@ -0,0 +1,192 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pattern matches a line against a template, a bit like fmt.Sscanf but with
+// named captures, e.g.
+//
+//	var nodePattern = parse.MustCompile("{node} = ({left}, {right})")
+//
+// Anything outside braces must appear exactly. "{name}" captures text up to
+// whatever comes next in the template, or the end of the line if nothing
+// does, and "{name:int}" captures an integer, skipping spaces before it.
+// Write "{{" or "}}" for a literal brace.
+type Pattern struct {
+	template string
+	tokens   []token
+}
+
+// token is one piece of a template, either literal text or a capture.
+type token struct {
+	literal string // Text which must appear, if this isn't a capture
+	name    string // Name of the capture
+	isInt   bool   // The capture is an integer
+}
+
+// Compile parses a template into a Pattern.
+func Compile(template string) (*Pattern, error) {
+	p := &Pattern{template: template}
+	names := make(map[string]bool)
+	var literal strings.Builder
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		// Doubled braces are literal
+		if (c == '{' || c == '}') && i+1 < len(template) && template[i+1] == c {
+			literal.WriteByte(c)
+			i++
+			continue
+		}
+		if c == '}' {
+			return nil, fmt.Errorf("pattern %q: unexpected } at %d", template, i+1)
+		}
+		if c != '{' {
+			literal.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(template[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("pattern %q: unclosed { at %d", template, i+1)
+		}
+		name, kind, _ := strings.Cut(template[i+1:i+end], ":")
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("pattern %q: capture at %d has no name", template, i+1)
+		case names[name]:
+			return nil, fmt.Errorf("pattern %q: capture %q used twice", template, name)
+		case kind != "" && kind != "int":
+			return nil, fmt.Errorf("pattern %q: unknown type %q for %q", template, kind, name)
+		}
+		names[name] = true
+
+		if literal.Len() > 0 {
+			p.tokens = append(p.tokens, token{literal: literal.String()})
+			literal.Reset()
+		} else if len(p.tokens) > 0 && p.tokens[len(p.tokens)-1].name != "" {
+			// With nothing between them there is no telling where the first
+			// capture ends
+			return nil, fmt.Errorf("pattern %q: captures need text between them", template)
+		}
+		p.tokens = append(p.tokens, token{name: name, isInt: kind == "int"})
+		i += end
+	}
+	if literal.Len() > 0 {
+		p.tokens = append(p.tokens, token{literal: literal.String()})
+	}
+	return p, nil
+}
+
+// MustCompile is like Compile but panics if the template is invalid. It is
+// meant for package level variables.
+func MustCompile(template string) *Pattern {
+	p, err := Compile(template)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the template the pattern was compiled from.
+func (p *Pattern) String() string {
+	return p.template
+}
+
+// leadingInt matches an integer at the start of the text.
+var leadingInt = regexp.MustCompile(`^-?\d+`)
+
+// Match matches the whole span against the pattern. The error gives the
+// position where the span stopped matching.
+func (p *Pattern) Match(s Span) (Match, error) {
+	m := Match{spans: make(map[string]Span), ints: make(map[string]int)}
+	text := s.Text
+	pos := 0
+
+	for i, t := range p.tokens {
+		switch {
+		case t.literal != "":
+			if !strings.HasPrefix(text[pos:], t.literal) {
+				return Match{}, s.Errorf(pos, "expected %q, got %q", t.literal, rest(text[pos:]))
+			}
+			pos += len(t.literal)
+
+		case t.isInt:
+			start := pos + len(text[pos:]) - len(strings.TrimLeft(text[pos:], " "))
+			digits := leadingInt.FindString(text[start:])
+			if digits == "" {
+				return Match{}, s.Errorf(start, "expected a number for %s, got %q", t.name, rest(text[start:]))
+			}
+			n, err := strconv.Atoi(digits)
+			if err != nil {
+				return Match{}, s.Errorf(start, "number %s for %s is too big", digits, t.name)
+			}
+			pos = start + len(digits)
+			m.spans[t.name] = s.Slice(start, pos)
+			m.ints[t.name] = n
+
+		default:
+			// Text runs up to the next literal, or to the end of the line
+			end := len(text)
+			if i+1 < len(p.tokens) {
+				next := p.tokens[i+1].literal
+				found := strings.Index(text[pos:], next)
+				if found < 0 {
+					return Match{}, s.Errorf(pos, "expected %q after %s, got %q", next, t.name, rest(text[pos:]))
+				}
+				end = pos + found
+			}
+			m.spans[t.name] = s.Slice(pos, end)
+			pos = end
+		}
+	}
+
+	if pos < len(text) {
+		return Match{}, s.Errorf(pos, "unexpected %q at end of line", rest(text[pos:]))
+	}
+	return m, nil
+}
+
+// rest shortens text for use in an error message.
+func rest(text string) string {
+	const max = 20
+	if len(text) > max {
+		return text[:max] + "..."
+	}
+	return text
+}
+
+// Match holds the captures from a successful Pattern.Match. Asking for a
+// capture which isn't in the pattern panics, as that is a mistake in the
+// code rather than the input.
+type Match struct {
+	spans map[string]Span
+	ints  map[string]int
+}
+
+// Span returns the named capture along with its position.
+func (m Match) Span(name string) Span {
+	s, ok := m.spans[name]
+	if !ok {
+		panic(fmt.Sprintf("parse: no capture named %q", name))
+	}
+	return s
+}
+
+// Text returns the text of the named capture.
+func (m Match) Text(name string) string {
+	return m.Span(name).Text
+}
+
+// Int returns the named "{name:int}" capture.
+func (m Match) Int(name string) int {
+	n, ok := m.ints[name]
+	if !ok {
+		panic(fmt.Sprintf("parse: no int capture named %q", name))
+	}
+	return n
+}
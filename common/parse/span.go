@@ -0,0 +1,185 @@
+// Package parse helps turn puzzle input into data while keeping track of
+// where each piece of text came from, so a malformed input is reported as
+// e.g. "input.txt:3:12: expected a number, got "x"" rather than a silent zero
+// or an index out of range panic.
+//
+// Input is read as Spans, a piece of text which remembers its file, line and
+// column. Splitting or trimming a Span gives smaller Spans which still know
+// their position.
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Span is a piece of one line of input and where it is.
+type Span struct {
+	File string // Input file name, or empty if not known
+	Line int    // Line number, counting from 1
+	Col  int    // Column of the first character of Text, counting from 1
+	Text string
+}
+
+// Lines returns a Span for each line of a file, numbered from 1.
+func Lines(file string, lines []string) []Span {
+	spans := make([]Span, len(lines))
+	for i, line := range lines {
+		spans[i] = Span{File: file, Line: i + 1, Col: 1, Text: line}
+	}
+	return spans
+}
+
+// Sections splits lines into the groups between blank lines, such as the
+// paragraphs of a puzzle input. Blank lines are dropped and so is any group
+// which would be empty.
+func Sections(lines []Span) [][]Span {
+	var sections [][]Span
+	var current []Span
+	for _, line := range lines {
+		if strings.TrimSpace(line.Text) == "" {
+			if len(current) > 0 {
+				sections = append(sections, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		sections = append(sections, current)
+	}
+	return sections
+}
+
+// String returns the text.
+func (s Span) String() string {
+	return s.Text
+}
+
+// Errorf returns an Error at the given byte offset into the span.
+func (s Span) Errorf(offset int, format string, args ...any) error {
+	return &Error{File: s.File, Line: s.Line, Col: s.Col + offset, Err: fmt.Errorf(format, args...)}
+}
+
+// Slice returns the span of Text[i:j].
+func (s Span) Slice(i, j int) Span {
+	s.Col += i
+	s.Text = s.Text[i:j]
+	return s
+}
+
+// TrimSpace returns the span without leading and trailing white space.
+func (s Span) TrimSpace() Span {
+	start := len(s.Text) - len(strings.TrimLeft(s.Text, " \t"))
+	end := len(strings.TrimRight(s.Text, " \t"))
+	if end < start {
+		end = start
+	}
+	return s.Slice(start, end)
+}
+
+// Cut splits the span around the first sep, like strings.Cut. If sep is
+// missing it returns the whole span, an empty span and false.
+func (s Span) Cut(sep string) (before, after Span, found bool) {
+	i := strings.Index(s.Text, sep)
+	if i < 0 {
+		return s, s.Slice(len(s.Text), len(s.Text)), false
+	}
+	return s.Slice(0, i), s.Slice(i+len(sep), len(s.Text)), true
+}
+
+// Split splits the span around every sep, like strings.Split.
+func (s Span) Split(sep string) []Span {
+	var parts []Span
+	for {
+		before, after, found := s.Cut(sep)
+		parts = append(parts, before)
+		if !found {
+			return parts
+		}
+		s = after
+	}
+}
+
+// Fields splits the span around runs of spaces, like strings.Fields.
+func (s Span) Fields() []Span {
+	var fields []Span
+	start := -1
+	for i, c := range s.Text {
+		space := c == ' ' || c == '\t'
+		switch {
+		case !space && start < 0:
+			start = i
+		case space && start >= 0:
+			fields = append(fields, s.Slice(start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, s.Slice(start, len(s.Text)))
+	}
+	return fields
+}
+
+// KeyValue splits a "key: value" record around the first sep, returning the
+// trimmed key and value. It is an error if sep is missing or the key is
+// empty.
+func (s Span) KeyValue(sep string) (string, Span, error) {
+	key, value, found := s.Cut(sep)
+	if !found {
+		return "", Span{}, s.Errorf(0, "expected %q in %q", sep, s.Text)
+	}
+	key = key.TrimSpace()
+	if key.Text == "" {
+		return "", Span{}, key.Errorf(0, "missing key before %q", sep)
+	}
+	return key.Text, value.TrimSpace(), nil
+}
+
+// Int converts the whole span, ignoring surrounding spaces, to an integer.
+func (s Span) Int() (int, error) {
+	t := s.TrimSpace()
+	n, err := strconv.Atoi(t.Text)
+	if err != nil {
+		if t.Text == "" {
+			return 0, t.Errorf(0, "expected a number")
+		}
+		return 0, t.Errorf(0, "expected a number, got %q", t.Text)
+	}
+	return n, nil
+}
+
+// FieldInts converts every field of the span to an integer, so unlike Ints
+// anything which isn't a number is an error.
+func (s Span) FieldInts() ([]int, error) {
+	var ints []int
+	for _, field := range s.Fields() {
+		n, err := field.Int()
+		if err != nil {
+			return nil, err
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+// intPattern matches an integer, including a leading minus sign.
+var intPattern = regexp.MustCompile(`-?\d+`)
+
+// Ints returns every integer found in the span, skipping anything else, so
+// "x=3, y=-12" gives [3 -12]. It is only an error if a number is too big for
+// an int.
+func (s Span) Ints() ([]int, error) {
+	var ints []int
+	for _, loc := range intPattern.FindAllStringIndex(s.Text, -1) {
+		n, err := strconv.Atoi(s.Text[loc[0]:loc[1]])
+		if err != nil {
+			return nil, s.Errorf(loc[0], "number %s is too big", s.Text[loc[0]:loc[1]])
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
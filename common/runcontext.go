@@ -0,0 +1,68 @@
+// Package common provides utility functions shared across the project.
+package common
+
+import (
+	"context"
+	"jonoricci/advent-of-code-go/common/viz"
+
+	"go.uber.org/zap"
+)
+
+// RunContext is passed to every solution function in place of package level
+// state. It carries the logger, the config and which input is being solved,
+// and is a context.Context which is cancelled when the run should stop, so
+// slow solutions can give up early.
+//
+// A RunContext is never changed once made, the With methods return copies, so
+// one can be shared by solutions running at the same time.
+type RunContext struct {
+	context.Context
+
+	Logger *zap.SugaredLogger
+	Config Config
+	Year   int
+	Day    int
+	Part   int    // 1 or 2, or 0 while parsing
+	Input  string // Input file name, relative to the day's directory
+
+	// Frames receives pictures of the puzzle from solutions which can draw
+	// it. It is nil, throwing them away, unless Config.Render is set.
+	Frames viz.Hook
+}
+
+// NewRunContext returns a RunContext for a day. The year and day are added to
+// every line the logger writes. A nil logger discards everything.
+func NewRunContext(ctx context.Context, logger *zap.SugaredLogger, cfg Config, year, day int) *RunContext {
+	if logger == nil {
+		logger = zap.NewNop().Sugar()
+	}
+	return &RunContext{
+		Context: ctx,
+		Logger:  logger.With("year", year, "day", day),
+		Config:  cfg,
+		Year:    year,
+		Day:     day,
+	}
+}
+
+// Background returns a RunContext which is never cancelled and discards its
+// logs, for calling solutions as a library or from tests.
+func Background() *RunContext {
+	return NewRunContext(context.Background(), nil, DefaultConfig(), 0, 0)
+}
+
+// WithInput returns a copy of rc solving the named input file.
+func (rc *RunContext) WithInput(input string) *RunContext {
+	c := *rc
+	c.Input = input
+	c.Logger = rc.Logger.With("input", input)
+	return &c
+}
+
+// WithPart returns a copy of rc running the given part.
+func (rc *RunContext) WithPart(part int) *RunContext {
+	c := *rc
+	c.Part = part
+	c.Logger = rc.Logger.With("part", part)
+	return &c
+}
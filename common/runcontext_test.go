@@ -0,0 +1,33 @@
+package common
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRunContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := NewRunContext(ctx, nil, DefaultConfig(), 2023, 7)
+
+	// The With methods must leave the original alone
+	part := rc.WithInput("test_input.txt").WithPart(2)
+	if rc.Input != "" || rc.Part != 0 {
+		t.Errorf("original changed to input %q part %d", rc.Input, rc.Part)
+	}
+	if part.Input != "test_input.txt" || part.Part != 2 || part.Year != 2023 || part.Day != 7 {
+		t.Errorf("got %d day %d input %q part %d", part.Year, part.Day, part.Input, part.Part)
+	}
+
+	// Cancelling the parent context reaches every copy
+	if part.Err() != nil {
+		t.Fatalf("Err() = %v before cancel", part.Err())
+	}
+	cancel()
+	if part.Err() != context.Canceled {
+		t.Errorf("Err() = %v after cancel, want %v", part.Err(), context.Canceled)
+	}
+
+	if Background().Err() != nil {
+		t.Error("Background() should never be cancelled")
+	}
+}
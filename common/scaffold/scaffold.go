@@ -0,0 +1,256 @@
+// Package scaffold creates the files for a new puzzle day from templates and
+// adds the day to the repository's README and the aoc command.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html"
+	"jonoricci/advent-of-code-go/common"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// Day describes the puzzle day being created.
+type Day struct {
+	Year      int
+	Day       int
+	Title     string
+	InputFile string       // Input the day's config starts with
+	Example   string       // Written to test_input.txt
+	Fuzz      []FuzzTarget // Parsing helpers to fuzz, see CreateTests
+}
+
+// Pad returns the day as a two digit string, as used in directory and package
+// names.
+func (d Day) Pad() string {
+	return fmt.Sprintf("%02d", d.Day)
+}
+
+// linkName returns the README link reference for the day, e.g. 23d07.
+func (d Day) linkName() string {
+	return fmt.Sprintf("%02dd%s", d.Year%100, d.Pad())
+}
+
+// files maps each template to the file it's rendered to in the day's directory.
+var files = map[string]string{
+	"main.go.tmpl":      "main.go",
+	"main_test.go.tmpl": "main_test.go",
+	"config.yaml.tmpl":  "config.yaml",
+	"README.md.tmpl":    "README.md",
+	"answers.yaml.tmpl": "answers.yaml",
+}
+
+// Create renders the templates into the day's directory below root and makes
+// sure empty input files exist. It refuses to touch a day which already has a
+// main.go and never overwrites an existing file. It returns the files written.
+func Create(root string, d Day) ([]string, error) {
+	dir := common.DayDir(root, d.Year, d.Day)
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); err == nil {
+		return nil, fmt.Errorf("%d day %s already exists in %s", d.Year, d.Pad(), dir)
+	}
+	if d.InputFile == "" {
+		d.InputFile = "input.txt"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	// Render in a fixed order so the output is predictable
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var written []string
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, name, d); err != nil {
+			return written, fmt.Errorf("rendering %s: %w", name, err)
+		}
+		path := filepath.Join(dir, files[name])
+		if err := writeNew(path, buf.Bytes()); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	// Inputs are pasted or downloaded in later, but must exist to run
+	inputs := map[string]string{"input.txt": "", "test_input.txt": d.Example}
+	for _, name := range []string{"input.txt", "test_input.txt"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := writeNew(path, []byte(inputs[name])); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// writeNew writes a file, failing if it already exists.
+func writeNew(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// UpdateReadme adds the day to the solutions table and link list in the
+// README at path. A day already in the table is replaced.
+func UpdateReadme(path string, d Day) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	// Find the year's column from the table header
+	column := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "| Day |") {
+			continue
+		}
+		for j, cell := range strings.Split(line, "|") {
+			if strings.TrimSpace(cell) == fmt.Sprint(d.Year) {
+				column = j
+			}
+		}
+		if column == -1 {
+			return fmt.Errorf("no %d column in the solutions table", d.Year)
+		}
+
+		// Rows follow the header and separator, one per day
+		row := i + 1 + d.Day
+		if row >= len(lines) || !strings.HasPrefix(lines[row], "| "+d.Pad()+" |") {
+			return fmt.Errorf("no row for day %s in the solutions table", d.Pad())
+		}
+		cells := strings.Split(lines[row], "|")
+		cells[column] = fmt.Sprintf(" [%s][%s] ", d.Title, d.linkName())
+		lines[row] = strings.Join(cells, "|")
+		break
+	}
+	if column == -1 {
+		return errors.New("solutions table not found")
+	}
+
+	lines = addLink(lines, d)
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644)
+}
+
+// addLink inserts the day's link reference next to the other links for the
+// same year, keeping them in day order.
+func addLink(lines []string, d Day) []string {
+	name := d.linkName()
+	link := fmt.Sprintf("[%s]: %d/day_%s/", name, d.Year, d.Pad())
+	yearPrefix := fmt.Sprintf("[%02dd", d.Year%100)
+
+	insertAt := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, yearPrefix) {
+			continue
+		}
+		existing := line[1:strings.Index(line, "]")]
+		if existing == name {
+			return lines // Already linked
+		}
+		if existing < name {
+			insertAt = i + 1
+		} else if insertAt == -1 {
+			insertAt = i
+		}
+	}
+
+	// A year with no links yet goes before the external links
+	if insertAt == -1 {
+		for i, line := range lines {
+			if strings.HasPrefix(line, "[url_") {
+				return insertLines(lines, i, link, "")
+			}
+		}
+		return append(lines, link)
+	}
+	return insertLines(lines, insertAt, link)
+}
+
+// insertLines returns lines with extra lines inserted at index i.
+func insertLines(lines []string, i int, extra ...string) []string {
+	result := make([]string, 0, len(lines)+len(extra))
+	result = append(result, lines[:i]...)
+	result = append(result, extra...)
+	return append(result, lines[i:]...)
+}
+
+// RegisterImport adds a blank import for the day to the aoc command's list of
+// solutions at path, keeping the imports sorted.
+func RegisterImport(path string, d Day) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	source := string(data)
+
+	line := fmt.Sprintf("\t_ \"jonoricci/advent-of-code-go/%d/day_%s\"", d.Year, d.Pad())
+	if strings.Contains(source, line+"\n") {
+		return nil
+	}
+
+	start := strings.Index(source, "import (\n")
+	end := strings.Index(source, "\n)")
+	if start == -1 || end == -1 {
+		return fmt.Errorf("no import block in %s", path)
+	}
+	start += len("import (\n")
+
+	imports := strings.Split(source[start:end], "\n")
+	imports = append(imports, line)
+	sort.Strings(imports)
+
+	source = source[:start] + strings.Join(imports, "\n") + source[end:]
+	return os.WriteFile(path, []byte(source), 0o644)
+}
+
+var (
+	titleRegex   = regexp.MustCompile(`<h2>--- Day \d+: (.*?) ---</h2>`)
+	exampleRegex = regexp.MustCompile(`(?s)<pre><code>(.*?)</code></pre>`)
+	tagRegex     = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseTitle finds the puzzle title in a puzzle's HTML page.
+func ParseTitle(page string) (string, error) {
+	match := titleRegex.FindStringSubmatch(page)
+	if match == nil {
+		return "", errors.New("puzzle title not found")
+	}
+	return html.UnescapeString(match[1]), nil
+}
+
+// ParseExample returns the first code block in a puzzle's HTML page, which is
+// nearly always the example input. It returns false if there isn't one.
+func ParseExample(page string) (string, bool) {
+	match := exampleRegex.FindStringSubmatch(page)
+	if match == nil {
+		return "", false
+	}
+	return html.UnescapeString(tagRegex.ReplaceAllString(match[1], "")), true
+}
@@ -0,0 +1,237 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/golden"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const readme = `| Day | 2023 | 2015 |
+|---|---|---|
+| 01 | [Trebuchet?!][23d01] | [Not Quite Lisp][15d01] |
+| 02 |  |  |
+| 03 |  |  |
+
+[15d01]: 2015/day_01/
+
+[23d01]: 2023/day_01/
+[23d03]: 2023/day_03/
+
+[url_aoc]: https://adventofcode.com/
+`
+
+// TestUpdateReadme ensures the table cell and link are added in place.
+func TestUpdateReadme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(path, []byte(readme), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpdateReadme(path, Day{Year: 2023, Day: 2, Title: "Cube Conundrum"}); err != nil {
+		t.Fatalf("UpdateReadme returned an error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	got := string(data)
+
+	if !strings.Contains(got, "| 02 | [Cube Conundrum][23d02] |  |\n") {
+		t.Errorf("Expected day 02 row to be filled in, got:\n%s", got)
+	}
+	if !strings.Contains(got, "[23d01]: 2023/day_01/\n[23d02]: 2023/day_02/\n[23d03]: 2023/day_03/\n") {
+		t.Errorf("Expected link to be added in day order, got:\n%s", got)
+	}
+
+	if err := UpdateReadme(path, Day{Year: 2019, Day: 1, Title: "x"}); err == nil {
+		t.Errorf("Expected an error for a year without a column")
+	}
+}
+
+// TestRegisterImport ensures the blank import is added once and sorted.
+func TestRegisterImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "solutions.go")
+	source := "package main\n\nimport (\n\t_ \"jonoricci/advent-of-code-go/2015/day_01\"\n\t_ \"jonoricci/advent-of-code-go/2023/day_01\"\n)\n"
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := Day{Year: 2016, Day: 1}
+	for i := 0; i < 2; i++ {
+		if err := RegisterImport(path, d); err != nil {
+			t.Fatalf("RegisterImport returned an error: %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "package main\n\nimport (\n\t_ \"jonoricci/advent-of-code-go/2015/day_01\"\n\t_ \"jonoricci/advent-of-code-go/2016/day_01\"\n\t_ \"jonoricci/advent-of-code-go/2023/day_01\"\n)\n"
+	if string(data) != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, data)
+	}
+}
+
+// TestCreate ensures a day is rendered and can't be created twice.
+func TestCreate(t *testing.T) {
+	root := t.TempDir()
+	d := Day{Year: 2023, Day: 7, Title: "Camel Cards", Example: "32T3K 765\n"}
+
+	written, err := Create(root, d)
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if len(written) != 7 {
+		t.Errorf("Expected 7 files to be written, got %v", written)
+	}
+
+	source, _ := os.ReadFile(filepath.Join(root, "2023", "day_07", "main.go"))
+	if !strings.Contains(string(source), "package day07") || !strings.Contains(string(source), "common.Register(2023, 7,") {
+		t.Errorf("Expected main.go to be rendered for day 07, got:\n%s", source)
+	}
+	example, _ := os.ReadFile(filepath.Join(root, "2023", "day_07", "test_input.txt"))
+	if string(example) != d.Example {
+		t.Errorf("Expected test input %q, got %q", d.Example, example)
+	}
+
+	if _, err := Create(root, d); err == nil {
+		t.Errorf("Expected an error creating an existing day")
+	}
+}
+
+// TestParsePuzzlePage ensures the title and example are found in a puzzle page.
+func TestParsePuzzlePage(t *testing.T) {
+	page := `<article class="day-desc"><h2>--- Day 7: Camel Cards ---</h2><p>For example:</p>` +
+		`<pre><code>32T3K 765
+T55J5 684
+</code></pre><p>Later:</p><pre><code><em>6440</em></code></pre></article>`
+
+	title, err := ParseTitle(page)
+	if err != nil || title != "Camel Cards" {
+		t.Errorf("Expected title %q, got %q (%v)", "Camel Cards", title, err)
+	}
+
+	example, ok := ParseExample(page)
+	if !ok || example != "32T3K 765\nT55J5 684\n" {
+		t.Errorf("Expected the first code block, got %q", example)
+	}
+}
+
+// TestFindFuzzTargets ensures only unexported helpers reading a string or
+// span first and returning an error are picked, and only when their other
+// parameters can be given a zero value without an import.
+func TestFindFuzzTargets(t *testing.T) {
+	source := `package day99
+
+func init() {}
+func Part1(line string) error { return nil }
+func searchLine(line string) (int, int, error) { return 0, 0, nil }
+func countCubes(subset parse.Span) (Cubes, error) { return Cubes{}, nil }
+func parseMap(lines []parse.Span) ([]RangeMap, error) { return nil, nil }
+func digitValue(match string, numberMap map[string]int) (int, error) { return 0, nil }
+func pad(line string, _, t int) error { return nil }
+func wait(line string, d time.Duration) error { return nil }
+func join(line string, more ...string) error { return nil }
+func isNumber(char rune) bool { return false }
+func noError(line string) int { return 0 }
+func (c Card) matches(line string) error { return nil }
+`
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := FindFuzzTargets(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FuzzTarget{
+		{Name: "searchLine", Kind: "string"},
+		{Name: "countCubes", Kind: "span"},
+		{Name: "parseMap", Kind: "spans"},
+		{Name: "digitValue", Kind: "string", Others: []Param{{"numberMap", "map[string]int"}}},
+		{Name: "pad", Kind: "string", Others: []Param{{"arg2", "int"}, {"arg3", "int"}}},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Errorf("Expected %+v, got %+v", want, targets)
+	}
+	if got := targets[1].FuzzName(); got != "FuzzCountCubes" {
+		t.Errorf("Expected FuzzCountCubes, got %s", got)
+	}
+}
+
+// TestCreateTests ensures answers are recorded for new inputs only and the
+// test file is written once.
+func TestCreateTests(t *testing.T) {
+	root := t.TempDir()
+	dir := common.DayDir(root, 1999, 1)
+	files := map[string]string{
+		"main.go":           "package day01\n\nfunc parseLine(line parse.Span) (int, error) { return 0, nil }\n",
+		"answers.yaml":      "test_input_01.txt:\n  part1: \"3\"\n",
+		"input.txt":         "a\nbb\ncc\n",
+		"test_input_01.txt": "a\nb\nc\n",
+		"test_input_02.txt": "x\n",
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Part 1 counts lines and Part 2 fails on a single line
+	puzzle := common.SolutionFuncs[[]string]{
+		Parser: common.ParseLines,
+		P1: func(rc *common.RunContext, input []string) (common.Answer, error) {
+			return common.Int(len(input)), nil
+		},
+		P2: func(rc *common.RunContext, input []string) (common.Answer, error) {
+			if len(input) == 1 {
+				return common.Answer{}, errors.New("too short")
+			}
+			return common.String(strings.Join(input, "")), nil
+		},
+	}
+	common.Register(1999, 1, puzzle)
+	p, err := common.Lookup(1999, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := RecordAnswers(context.Background(), dir, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(recorded, ",") != "input.txt,test_input_02.txt" {
+		t.Errorf("Expected input.txt and test_input_02.txt to be recorded, got %v", recorded)
+	}
+	answers, err := golden.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := golden.Answers{
+		"input.txt":         {Part1: "3", Part2: "abbcc", Unverified: true},
+		"test_input_01.txt": {Part1: "3"},
+		"test_input_02.txt": {Part1: "1", Unverified: true},
+	}
+	if !reflect.DeepEqual(answers, want) {
+		t.Errorf("Expected answers %v, got %v", want, answers)
+	}
+
+	path, err := CreateTests(root, Day{Year: 1999, Day: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, _ := os.ReadFile(path)
+	for _, want := range []string{"package day01", "golden.FuzzParts(f, \".\", 1999, 1)", "func FuzzParseLine(f *testing.F)", `"jonoricci/advent-of-code-go/common/parse"`} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("Expected main_test.go to contain %q, got:\n%s", want, source)
+		}
+	}
+	if _, err := CreateTests(root, Day{Year: 1999, Day: 1}); err == nil {
+		t.Error("Expected an error when the day already has tests")
+	}
+}
@@ -0,0 +1,265 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"jonoricci/advent-of-code-go/common"
+	"jonoricci/advent-of-code-go/common/golden"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// FuzzTarget is a function in a day's main.go which reads text and reports
+// bad input as an error, so it can be fuzzed to check it never panics.
+type FuzzTarget struct {
+	Name   string  // Function name, e.g. countCubes
+	Kind   string  // What it reads: "string", "span" for a parse.Span or "spans" for a []parse.Span
+	Others []Param // Any parameters after the text, which are passed their zero value
+}
+
+// Param is a parameter of a function, with its type as written in the source.
+type Param struct {
+	Name string
+	Type string
+}
+
+// FuzzName returns the name of the target's fuzz test, e.g. FuzzCountCubes.
+func (f FuzzTarget) FuzzName() string {
+	return "Fuzz" + strings.ToUpper(f.Name[:1]) + f.Name[1:]
+}
+
+// FuzzNeedsParse reports whether any of the day's fuzz tests use the parse
+// package.
+func (d Day) FuzzNeedsParse() bool {
+	for _, f := range d.Fuzz {
+		if f.Kind != "string" {
+			return true
+		}
+	}
+	return false
+}
+
+// FuzzNeedsCommon reports whether any of the day's fuzz tests use the common
+// package, to split a section into lines.
+func (d Day) FuzzNeedsCommon() bool {
+	for _, f := range d.Fuzz {
+		if f.Kind == "spans" {
+			return true
+		}
+	}
+	return false
+}
+
+// FindFuzzTargets looks through a Go file for unexported functions which take
+// a string, parse.Span or []parse.Span first and return an error last, the
+// shape of a day's parsing helpers such as countCubes or parseMap. Any other
+// parameters must have types the test file can name without importing
+// anything, such as searchLine's map[string]int.
+func FindFuzzTargets(path string) ([]FuzzTarget, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []FuzzTarget
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name == "init" || !unicode.IsLower(rune(fn.Name.Name[0])) {
+			continue
+		}
+		params := flattenParams(fn.Type.Params)
+		if len(params) == 0 || !returnsError(fn) {
+			continue
+		}
+		kind := inputKind(params[0].Type)
+		if kind == "" {
+			continue
+		}
+		others, ok := zeroableParams(params[1:])
+		if !ok {
+			continue
+		}
+		targets = append(targets, FuzzTarget{Name: fn.Name.Name, Kind: kind, Others: others})
+	}
+	return targets, nil
+}
+
+// field is one parameter of a function, as found in its declaration.
+type field struct {
+	Name string
+	Type ast.Expr
+}
+
+// flattenParams lists a function's parameters one at a time, splitting up
+// ones declared together such as (a, b string).
+func flattenParams(list *ast.FieldList) []field {
+	var params []field
+	for _, f := range list.List {
+		if len(f.Names) == 0 {
+			params = append(params, field{Type: f.Type})
+		}
+		for _, name := range f.Names {
+			params = append(params, field{Name: name.Name, Type: f.Type})
+		}
+	}
+	return params
+}
+
+// zeroableParams writes out the types of the parameters after the text, and
+// names each one for the variable holding its zero value. It returns false if
+// a type needs an import, as a package type such as time.Duration would, or
+// the function is variadic.
+func zeroableParams(params []field) ([]Param, bool) {
+	var others []Param
+	for i, p := range params {
+		local := true
+		ast.Inspect(p.Type, func(n ast.Node) bool {
+			switch n.(type) {
+			case *ast.SelectorExpr, *ast.Ellipsis:
+				local = false
+			}
+			return local
+		})
+		if !local {
+			return nil, false
+		}
+
+		var typ bytes.Buffer
+		if err := format.Node(&typ, token.NewFileSet(), p.Type); err != nil {
+			return nil, false
+		}
+		// The fuzz test already uses t and text, and _ can't be passed on
+		name := p.Name
+		if name == "" || name == "_" || name == "t" || name == "text" {
+			name = fmt.Sprintf("arg%d", i+2)
+		}
+		others = append(others, Param{Name: name, Type: typ.String()})
+	}
+	return others, true
+}
+
+// returnsError reports whether a function's last result is an error.
+func returnsError(fn *ast.FuncDecl) bool {
+	results := fn.Type.Results
+	if results == nil || len(results.List) == 0 {
+		return false
+	}
+	ident, ok := results.List[len(results.List)-1].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// inputKind names the kind of text a parameter type holds, or returns "" if
+// it isn't one a fuzz test can make.
+func inputKind(expr ast.Expr) string {
+	isSpan := func(expr ast.Expr) bool {
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		return ok && pkg.Name == "parse" && sel.Sel.Name == "Span"
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if t.Name == "string" {
+			return "string"
+		}
+	case *ast.SelectorExpr:
+		if isSpan(t) {
+			return "span"
+		}
+	case *ast.ArrayType:
+		if t.Len == nil && isSpan(t.Elt) {
+			return "spans"
+		}
+	}
+	return ""
+}
+
+// CreateTests writes a main_test.go for an existing day which has none. It
+// fuzzes the day's parts and each of the parsing helpers FindFuzzTargets finds
+// in main.go, while the answers in answers.yaml are checked by the tests in
+// cmd/aoc. It returns the file written.
+func CreateTests(root string, d Day) (string, error) {
+	dir := common.DayDir(root, d.Year, d.Day)
+	path := filepath.Join(dir, "main_test.go")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%d day %s already has tests in %s", d.Year, d.Pad(), path)
+	}
+
+	fuzz, err := FindFuzzTargets(filepath.Join(dir, "main.go"))
+	if err != nil {
+		return "", err
+	}
+	d.Fuzz = fuzz
+
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, "main_test.go.tmpl", d); err != nil {
+		return "", fmt.Errorf("rendering main_test.go.tmpl: %w", err)
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("formatting main_test.go: %w", err)
+	}
+	return path, writeNew(path, source)
+}
+
+// RecordAnswers runs a day's solution on each of its input files which has
+// no answers recorded yet, and records what both parts give. As these are
+// only the solution's own answers they are marked unverified until someone
+// checks them. A part which returns an error is left out, as is an input
+// which fails to parse. Inputs already in answers.yaml are left alone, even
+// if a part is missing, as some examples are only meant for one part. It
+// returns the inputs recorded.
+func RecordAnswers(ctx context.Context, dir string, puzzle common.Puzzle) ([]string, error) {
+	answers, err := golden.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := golden.InputFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var recorded []string
+	for _, input := range inputs {
+		if _, exists := answers[input]; exists {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, input))
+		if err != nil {
+			return recorded, err
+		}
+
+		rc := common.NewRunContext(ctx, nil, common.DefaultConfig(), 0, 0).WithInput(input)
+		parsed, err := puzzle.Parse(rc, common.SplitLines(string(data)))
+		if err != nil {
+			continue
+		}
+		parts := golden.Parts{Unverified: true}
+		if answer, err := puzzle.Part1(rc.WithPart(1), parsed); err == nil {
+			parts.Part1 = answer.String()
+		}
+		if answer, err := puzzle.Part2(rc.WithPart(2), parsed); err == nil {
+			parts.Part2 = answer.String()
+		}
+		if parts.Part1 == "" && parts.Part2 == "" {
+			continue
+		}
+		answers[input] = parts
+		recorded = append(recorded, input)
+	}
+
+	if len(recorded) == 0 {
+		return nil, nil
+	}
+	return recorded, golden.Save(dir, answers)
+}
@@ -0,0 +1,131 @@
+// Package common provides utility functions shared across the project.
+package common
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// Solution is implemented by every puzzle day. T is the day's parsed input,
+// so the input is parsed once and shared by both parts. Every function is
+// given the RunContext it runs in, so a day keeps no package level state and
+// can be run from anywhere, including at the same time as other days.
+type Solution[T any] interface {
+	Parse(rc *RunContext, input []string) (T, error)
+	Part1(rc *RunContext, input T) (Answer, error)
+	Part2(rc *RunContext, input T) (Answer, error)
+}
+
+// Puzzle is a Solution with its parsed input type hidden, so days with
+// different input types can be kept in the registry together.
+type Puzzle interface {
+	Parse(rc *RunContext, input []string) (any, error)
+	Part1(rc *RunContext, parsed any) (Answer, error)
+	Part2(rc *RunContext, parsed any) (Answer, error)
+}
+
+// puzzle wraps a Solution to implement Puzzle.
+type puzzle[T any] struct {
+	solution Solution[T]
+}
+
+func (p puzzle[T]) Parse(rc *RunContext, input []string) (any, error) {
+	return p.solution.Parse(rc, input)
+}
+
+func (p puzzle[T]) Part1(rc *RunContext, parsed any) (Answer, error) {
+	input, ok := parsed.(T)
+	if !ok {
+		return Answer{}, fmt.Errorf("parsed input is %T, expected %T", parsed, input)
+	}
+	return p.solution.Part1(rc, input)
+}
+
+func (p puzzle[T]) Part2(rc *RunContext, parsed any) (Answer, error) {
+	input, ok := parsed.(T)
+	if !ok {
+		return Answer{}, fmt.Errorf("parsed input is %T, expected %T", parsed, input)
+	}
+	return p.solution.Part2(rc, input)
+}
+
+// SolutionFuncs adapts a day's package level functions to the Solution
+// interface.
+type SolutionFuncs[T any] struct {
+	Parser func(rc *RunContext, input []string) (T, error)
+	P1     func(rc *RunContext, input T) (Answer, error)
+	P2     func(rc *RunContext, input T) (Answer, error)
+}
+
+// Parse runs the day's parser function.
+func (s SolutionFuncs[T]) Parse(rc *RunContext, input []string) (T, error) {
+	return s.Parser(rc, input)
+}
+
+// Part1 runs the day's Part1 function.
+func (s SolutionFuncs[T]) Part1(rc *RunContext, input T) (Answer, error) {
+	return s.P1(rc, input)
+}
+
+// Part2 runs the day's Part2 function.
+func (s SolutionFuncs[T]) Part2(rc *RunContext, input T) (Answer, error) {
+	return s.P2(rc, input)
+}
+
+// ParseLines is a parser for days which work on the raw input lines.
+func ParseLines(rc *RunContext, input []string) ([]string, error) {
+	return input, nil
+}
+
+// registry holds every registered solution keyed by year and then day.
+var registry = make(map[int]map[int]Puzzle)
+
+// Register adds a solution to the registry. It is intended to be called from
+// a day's init function and panics if the same year and day is registered
+// twice.
+func Register[T any](year, day int, s Solution[T]) {
+	if registry[year] == nil {
+		registry[year] = make(map[int]Puzzle)
+	}
+	if _, exists := registry[year][day]; exists {
+		panic(fmt.Sprintf("solution for %d day %02d already registered", year, day))
+	}
+	registry[year][day] = puzzle[T]{solution: s}
+}
+
+// Lookup returns the registered solution for a year and day.
+func Lookup(year, day int) (Puzzle, error) {
+	s, exists := registry[year][day]
+	if !exists {
+		return nil, fmt.Errorf("no solution registered for %d day %02d", year, day)
+	}
+	return s, nil
+}
+
+// Days returns the registered days for a year in ascending order.
+func Days(year int) []int {
+	var days []int
+	for day := range registry[year] {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
+
+// Years returns every year with at least one registered solution in ascending
+// order.
+func Years() []int {
+	var years []int
+	for year := range registry {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// DayDir returns the directory holding a day's config and inputs, relative to
+// the repository root.
+func DayDir(root string, year, day int) string {
+	return filepath.Join(root, fmt.Sprint(year), fmt.Sprintf("day_%02d", day))
+}
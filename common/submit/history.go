@@ -0,0 +1,114 @@
+package submit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryFileName is the file each day's submission history is kept in.
+const HistoryFileName = "history.json"
+
+// Attempt is a single submitted answer and the reply it got.
+type Attempt struct {
+	Part    int       `json:"part"`
+	Answer  string    `json:"answer"`
+	Outcome Outcome   `json:"outcome"`
+	Time    time.Time `json:"time"`
+}
+
+// History is every answer submitted for a day.
+type History struct {
+	Attempts []Attempt `json:"attempts"`
+
+	path string
+}
+
+// LoadHistory reads the submission history from a day's directory. A day with
+// no history file yet gets an empty history.
+func LoadHistory(dir string) (*History, error) {
+	h := &History{path: filepath.Join(dir, HistoryFileName)}
+
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", h.path, err)
+	}
+	return h, nil
+}
+
+// Save writes the history back to the file it was loaded from.
+func (h *History) Save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, append(data, '\n'), 0o644)
+}
+
+// Record adds a submitted answer and its result to the history.
+func (h *History) Record(part int, answer string, result Result, at time.Time) {
+	h.Attempts = append(h.Attempts, Attempt{
+		Part:    part,
+		Answer:  answer,
+		Outcome: result.Outcome,
+		Time:    at.UTC(),
+	})
+}
+
+// Check returns an error if submitting an answer for a part is pointless
+// given earlier attempts. That is when the part is already solved, the answer
+// is already known to be wrong, or a numeric answer is outside the bounds set
+// by earlier too high and too low replies.
+func (h *History) Check(part int, answer string) error {
+	value, numeric := new(big.Int).SetString(answer, 10)
+	var lowestTooHigh, highestTooLow *big.Int
+
+	for _, a := range h.Attempts {
+		if a.Part != part {
+			continue
+		}
+
+		switch a.Outcome {
+		case Correct:
+			return fmt.Errorf("part %d already solved with answer %s", part, a.Answer)
+		case Wrong, TooHigh, TooLow:
+			if a.Answer == answer {
+				return fmt.Errorf("answer %s was already submitted and is %s", answer, a.Outcome)
+			}
+		}
+
+		// Track the tightest bounds given by earlier replies
+		previous, ok := new(big.Int).SetString(a.Answer, 10)
+		if !ok {
+			continue
+		}
+		if a.Outcome == TooHigh && (lowestTooHigh == nil || previous.Cmp(lowestTooHigh) < 0) {
+			lowestTooHigh = previous
+		}
+		if a.Outcome == TooLow && (highestTooLow == nil || previous.Cmp(highestTooLow) > 0) {
+			highestTooLow = previous
+		}
+	}
+
+	if !numeric {
+		return nil
+	}
+	if lowestTooHigh != nil && value.Cmp(lowestTooHigh) >= 0 {
+		return fmt.Errorf("answer %s is too high, %s was already too high", answer, lowestTooHigh)
+	}
+	if highestTooLow != nil && value.Cmp(highestTooLow) <= 0 {
+		return fmt.Errorf("answer %s is too low, %s was already too low", answer, highestTooLow)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package submit posts answers to the Advent of Code website, interprets the
+// reply and keeps a history of every attempt.
+package submit
+
+import (
+	"fmt"
+	"html"
+	"jonoricci/advent-of-code-go/common/fetch"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcome is the kind of reply received for a submitted answer.
+type Outcome string
+
+// The possible outcomes of submitting an answer.
+const (
+	Correct       Outcome = "correct"
+	TooHigh       Outcome = "too high"
+	TooLow        Outcome = "too low"
+	Wrong         Outcome = "wrong"
+	RateLimited   Outcome = "rate limited"
+	AlreadySolved Outcome = "already solved"
+	Unknown       Outcome = "unknown"
+)
+
+// Result is the parsed reply to a submitted answer.
+type Result struct {
+	Outcome Outcome
+	Wait    time.Duration // How long to wait before trying again, if known
+	Message string        // Text of the reply with the HTML removed
+}
+
+// Submit posts an answer for a year, day and part and parses the reply.
+func Submit(client *fetch.Client, year, day, part int, answer string) (Result, error) {
+	if part != 1 && part != 2 {
+		return Result{}, fmt.Errorf("invalid part %d", part)
+	}
+
+	form := url.Values{}
+	form.Set("level", strconv.Itoa(part))
+	form.Set("answer", answer)
+
+	endpoint := fmt.Sprintf("%s/%d/day/%d/answer", client.BaseURL, year, day)
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	return ParseResponse(string(body)), nil
+}
+
+var (
+	articleRegex = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRegex     = regexp.MustCompile(`<[^>]*>`)
+	spaceRegex   = regexp.MustCompile(`\s+`)
+	waitRegex    = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	// Wrong answers say "please wait one minute" or "please wait 5 minutes"
+	waitMinutesRegex = regexp.MustCompile(`please wait (one|\d+) minutes?`)
+)
+
+// ParseResponse interprets the HTML page returned after submitting an answer.
+// Only the <article> element holds the reply, the rest is site chrome.
+func ParseResponse(page string) Result {
+	text := page
+	if match := articleRegex.FindStringSubmatch(page); match != nil {
+		text = match[1]
+	}
+	text = html.UnescapeString(tagRegex.ReplaceAllString(text, ""))
+	text = strings.TrimSpace(spaceRegex.ReplaceAllString(text, " "))
+
+	result := Result{Outcome: Unknown, Message: text}
+
+	switch {
+	case strings.Contains(text, "That's the right answer"):
+		result.Outcome = Correct
+	case strings.Contains(text, "You gave an answer too recently"):
+		result.Outcome = RateLimited
+		result.Wait = parseWait(text)
+	case strings.Contains(text, "You don't seem to be solving the right level"):
+		result.Outcome = AlreadySolved
+	case strings.Contains(text, "That's not the right answer"):
+		result.Outcome = Wrong
+		if strings.Contains(text, "your answer is too high") {
+			result.Outcome = TooHigh
+		} else if strings.Contains(text, "your answer is too low") {
+			result.Outcome = TooLow
+		}
+		// A wrong answer also starts a timeout before the next attempt
+		result.Wait = parseWait(text)
+	}
+
+	return result
+}
+
+// parseWait finds how long the reply asks us to wait before the next attempt.
+// It returns zero if the reply doesn't say.
+func parseWait(text string) time.Duration {
+	if match := waitRegex.FindStringSubmatch(text); match != nil {
+		minutes, _ := strconv.Atoi(match[1]) // Empty when under a minute
+		seconds, _ := strconv.Atoi(match[2])
+		return time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	}
+	if match := waitMinutesRegex.FindStringSubmatch(text); match != nil {
+		if match[1] == "one" {
+			return time.Minute
+		}
+		minutes, _ := strconv.Atoi(match[1])
+		return time.Duration(minutes) * time.Minute
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+package submit
+
+import (
+	"jonoricci/advent-of-code-go/common/fetch"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// page wraps a reply in the site chrome the real website returns.
+func page(article string) string {
+	return `<!DOCTYPE html><html><body><header><h1>Advent of Code</h1></header>` +
+		`<main><article><p>` + article + `</p></article></main></body></html>`
+}
+
+// TestParseResponse ensures each kind of reply is recognised.
+func TestParseResponse(t *testing.T) {
+	cases := []struct {
+		name    string
+		article string
+		outcome Outcome
+		wait    time.Duration
+	}{
+		{"correct", `That's the right answer!  You are <em>one gold star</em> closer to collecting enough star fruit.`, Correct, 0},
+		{"too high", `That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; please wait one minute before trying again. <a href="/2023/day/7">[Return to Day 7]</a>`, TooHigh, time.Minute},
+		{"too low", `That's not the right answer; your answer is too low.  please wait 5 minutes before trying again.`, TooLow, 5 * time.Minute},
+		{"wrong", `That's not the right answer.  If you're stuck, make sure you're using the full input data.`, Wrong, 0},
+		{"rate limited", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 37s left to wait.`, RateLimited, 4*time.Minute + 37*time.Second},
+		{"rate limited seconds", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 12s left to wait.`, RateLimited, 12 * time.Second},
+		{"already solved", `You don't seem to be solving the right level.  Did you already complete it?`, AlreadySolved, 0},
+		{"unknown", `Something else entirely.`, Unknown, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := ParseResponse(page(c.article))
+			if got.Outcome != c.outcome || got.Wait != c.wait {
+				t.Errorf("Expected %q waiting %v, got %q waiting %v", c.outcome, c.wait, got.Outcome, got.Wait)
+			}
+		})
+	}
+}
+
+// TestHistoryCheck ensures answers are refused based on earlier attempts.
+func TestHistoryCheck(t *testing.T) {
+	h := &History{}
+	at := time.Date(2023, time.December, 7, 5, 0, 0, 0, time.UTC)
+	h.Record(1, "500", Result{Outcome: TooHigh}, at)
+	h.Record(1, "600", Result{Outcome: TooHigh}, at)
+	h.Record(1, "100", Result{Outcome: TooLow}, at)
+	h.Record(1, "250", Result{Outcome: Wrong}, at)
+	h.Record(1, "300", Result{Outcome: RateLimited}, at)
+	h.Record(2, "42", Result{Outcome: Correct}, at)
+
+	cases := []struct {
+		part    int
+		answer  string
+		allowed bool
+	}{
+		{1, "250", false}, // Known wrong
+		{1, "500", false}, // Known too high
+		{1, "550", false}, // Above a too high answer
+		{1, "100", false}, // Known too low
+		{1, "50", false},  // Below a too low answer
+		{1, "300", true},  // Rate limited attempts weren't judged
+		{1, "499", true},
+		{1, "101", true},
+		{1, "abc", true}, // Non numeric answers only check exact matches
+		{2, "43", false}, // Already solved
+	}
+
+	for _, c := range cases {
+		err := h.Check(c.part, c.answer)
+		if (err == nil) != c.allowed {
+			t.Errorf("Check(%d, %q) returned %v, expected allowed to be %v", c.part, c.answer, err, c.allowed)
+		}
+	}
+}
+
+// TestHistorySaveLoad ensures the history survives a round trip to disk.
+func TestHistorySaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	h, err := LoadHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadHistory returned an error: %v", err)
+	}
+	h.Record(1, "6440", Result{Outcome: Correct}, time.Now())
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+
+	loaded, err := LoadHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadHistory returned an error: %v", err)
+	}
+	if len(loaded.Attempts) != 1 || loaded.Attempts[0].Answer != "6440" || loaded.Attempts[0].Outcome != Correct {
+		t.Errorf("Expected one correct attempt of 6440, got %+v", loaded.Attempts)
+	}
+}
+
+// TestSubmit ensures the answer is posted as a form and the reply parsed.
+func TestSubmit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2023/day/7/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("level") != "2" || r.FormValue("answer") != "5905" {
+			t.Errorf("Unexpected form values: %v", r.Form)
+		}
+		w.Write([]byte(page("That's the right answer!")))
+	}))
+	defer server.Close()
+
+	client := fetch.NewClient(server.URL, "secret")
+	result, err := Submit(client, 2023, 7, 2, "5905")
+	if err != nil {
+		t.Fatalf("Submit returned an error: %v", err)
+	}
+	if result.Outcome != Correct {
+		t.Errorf("Expected %q, got %q", Correct, result.Outcome)
+	}
+}
@@ -2,14 +2,15 @@
 package common
 
 import (
-	"log/slog"
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 // SumStrings takes a slice of strings, converts each to an integer, and returns
-// the sum. If a string cannot be converted to an integer, the function logs a
-// fatal error and stops execution.
-func SumStrings(s []string) int {
+// the sum. Empty strings are skipped, and any other string which isn't an
+// integer is returned as an error.
+func SumStrings(s []string) (int, error) {
 	sum := 0 // Initialise sum to zero
 
 	for _, str := range s {
@@ -21,14 +22,14 @@ func SumStrings(s []string) int {
 		num, err := strconv.Atoi(str)
 
 		if err != nil {
-			slog.Error("SumStrings Failed:", err)
+			return 0, fmt.Errorf("summing strings: %w", err)
 		}
 
 		// Add converted int to total sum
 		sum += num
 	}
 
-	return sum
+	return sum, nil
 }
 
 // SumInts takes a slice of integers and returns their sum.
@@ -55,3 +56,18 @@ func RemoveEmptyStrings(s []string) []string {
 
 	return r
 }
+
+// SplitLines splits puzzle input into lines. Windows line endings are
+// converted and blank lines at the end of the input are dropped, but blank
+// lines elsewhere are kept as empty strings, as some puzzles use them to
+// separate sections of the input and line numbers in errors stay correct.
+func SplitLines(input string) []string {
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+	lines := strings.Split(input, "\n")
+
+	// Remove the trailing newline and any blank lines before it
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitLines ensures Windows line endings are converted and only the
+// blank lines at the end are dropped.
+func TestSplitLines(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"unix", "a\nb\n", []string{"a", "b"}},
+		{"windows", "a\r\nb\r\n", []string{"a", "b"}},
+		{"sections", "a\n\nb\n\n\n", []string{"a", "", "b"}},
+		{"leading spaces kept", "  a\n b\n", []string{"  a", " b"}},
+		{"no final newline", "a", []string{"a"}},
+		{"empty", "\n\n", []string{}},
+	}
+	for _, c := range cases {
+		if got := SplitLines(c.input); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expected %q, got %q", c.name, c.want, got)
+		}
+	}
+}
+
+// TestSumStrings ensures empty strings are skipped and anything else which
+// isn't a number is an error.
+func TestSumStrings(t *testing.T) {
+	if got, err := SumStrings([]string{"1", "", "-2", "30"}); err != nil || got != 29 {
+		t.Errorf("Expected 29, got %d %v", got, err)
+	}
+	if _, err := SumStrings([]string{"1", "two"}); err == nil {
+		t.Error("Expected an error for a string which isn't a number")
+	}
+}
@@ -0,0 +1,145 @@
+package viz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Keys the player responds to while animating.
+const (
+	keyPause = ' ' // Pause or carry on
+	keyStep  = 'n' // Show the next frame while paused
+	keyQuit  = 'q' // Skip the rest of the frames
+)
+
+// clearScreen moves the cursor to the top left and clears the terminal, so
+// each frame is drawn over the last.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// Player shows frames one after another at a steady rate. On a terminal each
+// frame replaces the last, in colour, and keys pause, step through or skip
+// the animation. Anywhere else, such as when output is piped to a file, the
+// frames are written one after another in plain text without waiting.
+type Player struct {
+	mu       sync.Mutex // A solution may draw from more than one goroutine
+	ctx      context.Context
+	out      io.Writer
+	terminal bool
+	delay    time.Duration
+	keys     chan byte
+	restore  func()
+	paused   bool
+	skipping bool
+}
+
+// NewPlayer returns a player writing to out at fps frames per second, reading
+// keys from in when both are terminals. Once ctx is cancelled it stops
+// waiting, even when paused, and draws no more frames. Call Close when done
+// to put the terminal back how it was.
+//
+// Only one player should read a terminal at a time, as each takes every key
+// pressed and switches the terminal's mode.
+func NewPlayer(ctx context.Context, out io.Writer, in io.Reader, fps int) *Player {
+	p := &Player{ctx: ctx, out: out, terminal: IsTerminal(out)}
+	if fps > 0 {
+		p.delay = time.Second / time.Duration(fps)
+	}
+
+	if f, ok := in.(*os.File); ok && p.terminal && IsTerminal(f) {
+		// Without raw mode keys still arrive, but only once Enter is pressed
+		if restore, err := rawMode(f); err == nil {
+			p.restore = restore
+		}
+		p.keys = make(chan byte)
+		go readKeys(f, p.keys)
+	}
+	return p
+}
+
+// readKeys sends each byte read to keys until reading fails. It is left
+// blocked on the read when the program finishes, as there's no way to
+// interrupt it.
+func readKeys(in io.Reader, keys chan<- byte) {
+	buf := make([]byte, 1)
+	for {
+		if _, err := in.Read(buf); err != nil {
+			close(keys)
+			return
+		}
+		keys <- buf[0]
+	}
+}
+
+// Show draws a frame then waits until the next is due, or longer if the
+// animation is paused. It is a Hook, so can be given to solutions.
+func (p *Player) Show(f Frame) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.skipping || p.ctx.Err() != nil {
+		return
+	}
+
+	if !p.terminal {
+		fmt.Fprintln(p.out, f.Render(false))
+		return
+	}
+	help := "space: pause, q: skip"
+	if p.paused {
+		help = "paused, space: carry on, n: next frame, q: skip"
+	}
+	if p.keys == nil {
+		help = ""
+	}
+	fmt.Fprint(p.out, clearScreen+f.Render(true)+help+"\n")
+	p.wait()
+}
+
+// wait holds the next frame back until it is due, handling keys pressed in
+// the meantime. A cancelled run stops the waiting, so Ctrl-C still works
+// while paused.
+func (p *Player) wait() {
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+	for {
+		var tick <-chan time.Time
+		if !p.paused {
+			tick = timer.C
+		}
+		select {
+		case <-tick:
+			return
+		case <-p.ctx.Done():
+			p.skipping, p.paused = true, false
+			return
+		case key, ok := <-p.keys:
+			if !ok {
+				// Nothing more can be read, so stop waiting for keys
+				p.keys, p.paused = nil, false
+				continue
+			}
+			switch key {
+			case keyPause:
+				p.paused = !p.paused
+			case keyStep:
+				if p.paused {
+					return
+				}
+			case keyQuit:
+				p.skipping, p.paused = true, false
+				return
+			}
+		}
+	}
+}
+
+// Close puts the terminal back how it was before the player started.
+func (p *Player) Close() {
+	if p.restore != nil {
+		p.restore()
+		p.restore = nil
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package viz
+
+import (
+	"errors"
+	"os"
+)
+
+// rawMode isn't supported here, so keys only arrive once Enter is pressed.
+func rawMode(f *os.File) (restore func(), err error) {
+	return nil, errors.New("reading single key presses isn't supported on this system")
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package viz
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// rawMode makes the terminal pass on each key as it is pressed, without
+// waiting for Enter or echoing it, and returns a function which undoes it.
+// It uses stty rather than system calls so it needs no extra dependencies.
+func rawMode(f *os.File) (restore func(), err error) {
+	saved, err := stty(f, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(f, "-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() { stty(f, strings.TrimSpace(saved)) }, nil
+}
+
+// stty runs the stty command on the terminal f.
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,125 @@
+// Package viz draws grids to the terminal, with cells picked out in colour,
+// and plays a series of them as an animation so a solution can be watched
+// working, such as a search spreading out across a map.
+//
+// A solution builds a Frame for each picture, usually with GridFrame, and
+// passes it to a Hook. When nothing is watching the Hook is nil and frames go
+// nowhere, so solutions can check Enabled before doing the work to make them.
+package viz
+
+import (
+	"io"
+	"jonoricci/advent-of-code-go/common/grid"
+	"os"
+	"strings"
+)
+
+// Style is an ANSI escape code which colours the text after it.
+type Style string
+
+// Styles for highlighting cells. An empty Style leaves a cell as it is.
+const (
+	Reset   Style = "\x1b[0m"
+	Bold    Style = "\x1b[1m"
+	Dim     Style = "\x1b[2m"
+	Red     Style = "\x1b[31m"
+	Green   Style = "\x1b[32m"
+	Yellow  Style = "\x1b[33m"
+	Blue    Style = "\x1b[34m"
+	Magenta Style = "\x1b[35m"
+	Cyan    Style = "\x1b[36m"
+)
+
+// Cell is the text drawn for one grid cell and how to colour it.
+type Cell struct {
+	Text  string
+	Style Style
+}
+
+// Frame is one picture, such as a grid after a step of a search.
+type Frame struct {
+	Title string // Drawn above the cells, e.g. "Step 12"
+	Cells [][]Cell
+}
+
+// Highlight colours a set of cells.
+type Highlight struct {
+	Style  Style
+	Points []grid.Point
+}
+
+// GridFrame makes a frame from a grid, using text to draw each cell. Cells
+// in a highlight take its style, with later highlights winning where they
+// overlap.
+func GridFrame[T any](title string, g *grid.Grid[T], text func(grid.Point, T) string, highlights ...Highlight) Frame {
+	cells := make([][]Cell, g.Height())
+	for y := range cells {
+		cells[y] = make([]Cell, g.Width())
+	}
+	g.All(func(p grid.Point, v T) bool {
+		cells[p.Y][p.X] = Cell{Text: text(p, v)}
+		return true
+	})
+	for _, h := range highlights {
+		for _, p := range h.Points {
+			if g.InBounds(p) {
+				cells[p.Y][p.X].Style = h.Style
+			}
+		}
+	}
+	return Frame{Title: title, Cells: cells}
+}
+
+// Runes draws a cell of a grid of runes as itself, for use with GridFrame.
+func Runes(_ grid.Point, r rune) string {
+	return string(r)
+}
+
+// Render draws the frame one row per line, with the title first if there is
+// one. Colour can be turned off for output which isn't going to a terminal.
+func (f Frame) Render(colour bool) string {
+	var sb strings.Builder
+	if f.Title != "" {
+		sb.WriteString(f.Title)
+		sb.WriteByte('\n')
+	}
+	for _, row := range f.Cells {
+		for _, cell := range row {
+			if colour && cell.Style != "" {
+				sb.WriteString(string(cell.Style) + cell.Text + string(Reset))
+			} else {
+				sb.WriteString(cell.Text)
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Hook receives the frames a solution makes. A nil Hook throws them away.
+type Hook func(Frame)
+
+// Enabled reports whether anything is receiving frames, so solutions can skip
+// making them otherwise.
+func (h Hook) Enabled() bool {
+	return h != nil
+}
+
+// Emit passes the frame to the hook, if there is one.
+func (h Hook) Emit(f Frame) {
+	if h != nil {
+		h(f)
+	}
+}
+
+// IsTerminal reports whether w is a terminal, rather than a file or pipe, and
+// so whether it can show colours and redraw frames in place. Setting the
+// NO_COLOR environment variable turns this off.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,131 @@
+package viz
+
+import (
+	"bytes"
+	"context"
+	"jonoricci/advent-of-code-go/common/grid"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGridFrame ensures cells take the style of the last highlight covering
+// them and colour is only drawn when asked for.
+func TestGridFrame(t *testing.T) {
+	g, err := grid.FromLines([]string{"ab", "cd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := GridFrame("Title", g, Runes,
+		Highlight{Style: Red, Points: []grid.Point{{X: 0, Y: 0}, {X: 1, Y: 1}}},
+		Highlight{Style: Green, Points: []grid.Point{{X: 1, Y: 1}, {X: 5, Y: 5}}},
+	)
+
+	if got, want := f.Render(false), "Title\nab\ncd\n"; got != want {
+		t.Errorf("Expected plain frame %q, got %q", want, got)
+	}
+	want := "Title\n" + string(Red) + "a" + string(Reset) + "b\nc" + string(Green) + "d" + string(Reset) + "\n"
+	if got := f.Render(true); got != want {
+		t.Errorf("Expected coloured frame %q, got %q", want, got)
+	}
+}
+
+// TestHook ensures a nil hook can be used without checking it first.
+func TestHook(t *testing.T) {
+	var h Hook
+	if h.Enabled() {
+		t.Error("Expected a nil hook to be disabled")
+	}
+	h.Emit(Frame{})
+
+	var got []string
+	h = func(f Frame) { got = append(got, f.Title) }
+	h.Emit(Frame{Title: "one"})
+	if !h.Enabled() || len(got) != 1 || got[0] != "one" {
+		t.Errorf("Expected the frame to reach the hook, got %v", got)
+	}
+}
+
+// TestPlayerPlain ensures frames not going to a terminal are written one
+// after another without colour or waiting.
+func TestPlayerPlain(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPlayer(context.Background(), &out, strings.NewReader(""), 1)
+	defer p.Close()
+
+	start := time.Now()
+	p.Show(Frame{Title: "1", Cells: [][]Cell{{{Text: "x", Style: Red}}}})
+	p.Show(Frame{Title: "2", Cells: [][]Cell{{{Text: "y"}}}})
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected plain frames not to wait, took %v", elapsed)
+	}
+	if got, want := out.String(), "1\nx\n\n2\ny\n\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestPlayerKeys ensures the pause, step and skip keys control a terminal
+// player.
+func TestPlayerKeys(t *testing.T) {
+	var out bytes.Buffer
+	keys := make(chan byte)
+	p := &Player{ctx: context.Background(), out: &out, terminal: true, delay: time.Hour, keys: keys}
+	frame := Frame{Title: "frame"}
+
+	// Pausing then stepping moves on to the next frame despite the delay
+	done := make(chan bool)
+	go func() {
+		p.Show(frame)
+		done <- true
+	}()
+	keys <- keyPause
+	keys <- keyStep
+	<-done
+	if !p.paused {
+		t.Error("Expected the player to still be paused after stepping")
+	}
+
+	// Skipping stops drawing any more frames
+	go func() {
+		p.Show(frame)
+		done <- true
+	}()
+	keys <- keyQuit
+	<-done
+	drawn := out.Len()
+	p.Show(frame)
+	if out.Len() != drawn {
+		t.Error("Expected no frames to be drawn after skipping")
+	}
+	if got := strings.Count(out.String(), clearScreen); got != 2 {
+		t.Errorf("Expected 2 frames drawn over each other, got %d", got)
+	}
+}
+
+// TestPlayerCancel ensures cancelling the run stops a paused player waiting,
+// and no more frames are drawn after.
+func TestPlayerCancel(t *testing.T) {
+	var out bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	keys := make(chan byte)
+	p := &Player{ctx: ctx, out: &out, terminal: true, delay: time.Hour, keys: keys}
+
+	done := make(chan bool)
+	go func() {
+		p.Show(Frame{Title: "frame"})
+		done <- true
+	}()
+	keys <- keyPause
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected cancelling to stop the paused player")
+	}
+
+	drawn := out.Len()
+	p.Show(Frame{Title: "frame"})
+	if out.Len() != drawn {
+		t.Error("Expected no frames to be drawn after cancelling")
+	}
+}